@@ -0,0 +1,93 @@
+// mandos2kast converts all JSON tests and scenarios in a directory to KAST, or KORE, readable by K.
+//
+// Usage:
+//
+//	mandos2kast [flags] <test directory>
+//
+// Contracts are assembled by an external command, given with -assembler,
+// which is called with the code value from the test as its last argument and prints the assembled code.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	ojkast "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson2kast"
+)
+
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func profileByName(name string) (*ojkast.LabelProfile, error) {
+	switch strings.ToLower(name) {
+	case "iele":
+		return ojkast.IELEProfile(), nil
+	case "mandos-wasm":
+		return ojkast.MandosWasmProfile(), nil
+	default:
+		return nil, fmt.Errorf("unknown profile: %s", name)
+	}
+}
+
+func main() {
+	suffixes := flag.String("suffix", ".test.json,.scen.json", "comma-separated suffixes of the files to convert")
+	excluded := flag.String("exclude", "", "comma-separated patterns of files to skip, relative to the test directory")
+	outputPath := flag.String("out", "", "output directory, mirroring the test directory; by default, KAST files go next to the tests")
+	parallelism := flag.Int("j", 0, "number of files converted in parallel; by default, the number of CPUs")
+	force := flag.Bool("force", false, "convert all files, even if up to date")
+	assembler := flag.String("assembler", "", "command that assembles contract code, with arguments separated by spaces")
+	profileName := flag.String("profile", "iele", "K labels to use: iele or mandos-wasm")
+	kore := flag.Bool("kore", false, "write KORE instead of KAST")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <test directory>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	profile, err := profileByName(*profileName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	options := ojkast.BatchOptions{
+		AllowedSuffixes:      splitList(*suffixes),
+		ExcludedFilePatterns: splitList(*excluded),
+		OutputPath:           *outputPath,
+		Parallelism:          *parallelism,
+		Force:                *force,
+		Profile:              profile,
+		Kore:                 *kore,
+	}
+	if assemblerArgs := strings.Fields(*assembler); len(assemblerArgs) > 0 {
+		options.CodeProcessor = &ojkast.ExternalCodeProcessor{
+			Command: assemblerArgs[0],
+			Args:    assemblerArgs[1:],
+		}
+	}
+
+	summary, err := ojkast.ConvertDirectory(flag.Arg(0), "", options)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for _, failure := range summary.Failures {
+		fmt.Printf("FAIL: %s\n", failure.Error())
+	}
+	fmt.Printf("Done. %s\n", summary.String())
+	if len(summary.Failures) > 0 {
+		os.Exit(1)
+	}
+}
@@ -7,4 +7,5 @@ require (
 	github.com/ElrondNetwork/elrond-vm-common v0.3.3
 	github.com/stretchr/testify v1.4.0
 	golang.org/x/crypto v0.0.0-20191011191535-87dc89f01550
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -5,10 +5,13 @@ import (
 
 	"crypto/sha256"
 
+	vmcommon "github.com/ElrondNetwork/elrond-vm-common"
 	"golang.org/x/crypto/ripemd160"
 	"golang.org/x/crypto/sha3"
 )
 
+var _ vmcommon.CryptoHook = (*KryptoHookMock)(nil)
+
 // KryptoHookMock is a krypto hook implementation that we use for VM tests
 type KryptoHookMock int
 
@@ -7,6 +7,8 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+
+	mtp "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/testpaths"
 )
 
 // RunAllJSONScenariosInDirectory walks directory, parses and prepares all json scenarios,
@@ -22,8 +24,13 @@ func (r *ScenarioRunner) RunAllJSONScenariosInDirectory(
 
 	err := filepath.Walk(mainDirPath, func(testFilePath string, info os.FileInfo, err error) error {
 		if strings.HasSuffix(testFilePath, allowedSuffix) {
-			fmt.Printf("Scenario: %s ... ", shortenTestPath(testFilePath, generalTestPath))
-			if isExcluded(excludedFilePatterns, testFilePath, generalTestPath) {
+			fmt.Printf("Scenario: %s ... ", mtp.ShortenTestPath(testFilePath, generalTestPath))
+			excluded, err := mtp.IsExcluded(excludedFilePatterns, testFilePath, generalTestPath)
+			if err != nil {
+				fmt.Print("  error\n")
+				return err
+			}
+			if excluded {
 				nrSkipped++
 				fmt.Print("  skip\n")
 			} else {
@@ -4,12 +4,23 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	mj "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/model"
 	mjwrite "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/write"
+	oj "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson"
 )
 
+// jsoncExtension marks scenario files that contain comments and trailing commas.
+// They are parsed in the JSONC dialect, regardless of the parser options.
+const jsoncExtension = ".jsonc"
+
+// yamlExtension marks scenario files written in YAML.
+const yamlExtension = ".yaml"
+
 // RunSingleJSONScenario parses and prepares test, then calls testCallback.
+// Files ending in ".jsonc" (e.g. ".scen.jsonc") are always parsed as JSONC.
+// Files ending in ".yaml" (e.g. ".scen.yaml") are parsed as YAML.
 func (r *ScenarioRunner) RunSingleJSONScenario(contextPath string) error {
 	var err error
 	contextPath, err = filepath.Abs(contextPath)
@@ -33,10 +44,21 @@ func (r *ScenarioRunner) RunSingleJSONScenario(contextPath string) error {
 		return err
 	}
 
+	parser := r.Parser
+	if strings.HasSuffix(contextPath, jsoncExtension) {
+		parser.JSONOptions.Dialect = oj.DialectJSONC
+	}
+
 	r.Parser.ValueInterpreter.FileResolver.SetContext(contextPath)
-	scenario, parseErr := r.Parser.ParseScenarioFile(byteValue)
+	var scenario *mj.Scenario
+	var parseErr error
+	if strings.HasSuffix(contextPath, yamlExtension) {
+		scenario, parseErr = parser.ParseScenarioYAMLFile(byteValue)
+	} else {
+		scenario, parseErr = parser.ParseScenarioFile(byteValue)
+	}
 	if parseErr != nil {
-		return parseErr
+		return errorWithFile(contextPath, parseErr)
 	}
 
 	return r.Executor.ExecuteScenario(scenario, r.Parser.ValueInterpreter.FileResolver)
@@ -46,6 +68,9 @@ func (r *ScenarioRunner) RunSingleJSONScenario(contextPath string) error {
 // use with extreme caution
 func saveModifiedScenario(toPath string, scenario *mj.Scenario) {
 	resultJSON := mjwrite.ScenarioToJSONString(scenario)
+	if strings.HasSuffix(toPath, yamlExtension) {
+		resultJSON = mjwrite.ScenarioToYAMLString(scenario)
+	}
 
 	err := os.MkdirAll(filepath.Dir(toPath), os.ModePerm)
 	if err != nil {
@@ -7,21 +7,9 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
-)
 
-func isExcluded(excludedFilePatterns []string, testPath string, generalTestPath string) bool {
-	for _, et := range excludedFilePatterns {
-		excludedFullPath := path.Join(generalTestPath, et)
-		match, err := filepath.Match(excludedFullPath, testPath)
-		if err != nil {
-			panic(err)
-		}
-		if match {
-			return true
-		}
-	}
-	return false
-}
+	mtp "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/testpaths"
+)
 
 // RunAllJSONTestsInDirectory walks directory, parses and prepares all json tests,
 // then calls testExecutor for each of them.
@@ -36,8 +24,13 @@ func (r *TestRunner) RunAllJSONTestsInDirectory(
 
 	err := filepath.Walk(mainDirPath, func(testFilePath string, info os.FileInfo, err error) error {
 		if strings.HasSuffix(testFilePath, allowedSuffix) {
-			fmt.Printf("Test: %s ... ", shortenTestPath(testFilePath, generalTestPath))
-			if isExcluded(excludedFilePatterns, testFilePath, generalTestPath) {
+			fmt.Printf("Test: %s ... ", mtp.ShortenTestPath(testFilePath, generalTestPath))
+			excluded, err := mtp.IsExcluded(excludedFilePatterns, testFilePath, generalTestPath)
+			if err != nil {
+				fmt.Print("  error\n")
+				return err
+			}
+			if excluded {
 				nrSkipped++
 				fmt.Print("  skip\n")
 			} else {
@@ -63,10 +56,3 @@ func (r *TestRunner) RunAllJSONTestsInDirectory(
 
 	return nil
 }
-
-func shortenTestPath(path string, generalTestPath string) string {
-	if strings.HasPrefix(path, generalTestPath+"/") {
-		return path[len(generalTestPath)+1:]
-	}
-	return path
-}
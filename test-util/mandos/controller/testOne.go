@@ -37,7 +37,7 @@ func (r *TestRunner) RunSingleJSONTest(contextPath string) error {
 	r.Parser.ValueInterpreter.FileResolver.SetContext(contextPath)
 	top, parseErr := r.Parser.ParseTestFile(byteValue)
 	if parseErr != nil {
-		return parseErr
+		return errorWithFile(contextPath, parseErr)
 	}
 
 	for _, test := range top {
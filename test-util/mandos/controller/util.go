@@ -1,7 +1,10 @@
 package mandoscontroller
 
 import (
+	"fmt"
+
 	fr "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/fileresolver"
+	oj "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson"
 )
 
 // NewDefaultFileResolver yields a new DefaultFileResolver instance.
@@ -10,3 +13,12 @@ import (
 func NewDefaultFileResolver() *fr.DefaultFileResolver {
 	return fr.NewDefaultFileResolver()
 }
+
+// errorWithFile prefixes an error with the path of the file that caused it.
+// Positioned errors come out in the usual "file:line:column: message" format.
+func errorWithFile(filePath string, err error) error {
+	if _, isPositioned := err.(*oj.PositionError); isPositioned {
+		return fmt.Errorf("%s:%w", filePath, err)
+	}
+	return fmt.Errorf("%s: %w", filePath, err)
+}
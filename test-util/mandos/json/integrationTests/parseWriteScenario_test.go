@@ -1,9 +1,11 @@
 package mandosjsontest
 
 import (
+	"encoding/hex"
 	"testing"
 
 	fr "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/fileresolver"
+	mj "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/model"
 	mjparse "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/parse"
 	mjwrite "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/write"
 	"github.com/stretchr/testify/require"
@@ -28,3 +30,72 @@ func TestWriteScenario(t *testing.T) {
 
 	require.Equal(t, contents, []byte(serialized))
 }
+
+func TestWriteScenarioYAML(t *testing.T) {
+	contents, err := loadExampleFile("example.scen.json")
+	require.Nil(t, err)
+
+	p := mjparse.NewParser(
+		fr.NewDefaultFileResolver().ReplacePath(
+			"smart-contract.wasm",
+			"exampleFile.txt"))
+
+	scenario, parseErr := p.ParseScenarioFile(contents)
+	require.Nil(t, parseErr)
+
+	// JSON -> YAML -> JSON yields the original file
+	yamlScenario, parseErr := p.ParseScenarioYAMLFile([]byte(mjwrite.ScenarioToYAMLString(scenario)))
+	require.Nil(t, parseErr)
+	require.Equal(t, contents, []byte(mjwrite.ScenarioToJSONString(yamlScenario)))
+}
+
+func TestWriteScenarioPlainNumbers(t *testing.T) {
+	contents := []byte(`{
+    "name": "plain numbers",
+    "steps": [
+        {
+            "step": "setState",
+            "accounts": {
+                "address:owner": {
+                    "nonce": 1e3,
+                    "balance": 123456789012345678901234567890,
+                    "storage": {},
+                    "code": ""
+                }
+            }
+        },
+        {
+            "step": "checkState",
+            "accounts": {
+                "address:owner": {
+                    "nonce": 1000,
+                    "balance": "*",
+                    "storage": {},
+                    "code": ""
+                }
+            }
+        }
+    ]
+}
+`)
+	p := mjparse.NewParser(fr.NewDefaultFileResolver())
+	scenario, parseErr := p.ParseScenarioFile(contents)
+	require.Nil(t, parseErr)
+	require.Equal(t, uint64(1000), scenario.Steps[0].(*mj.SetStateStep).Accounts[0].Nonce.Value)
+
+	require.Equal(t, string(contents), mjwrite.ScenarioToJSONString(scenario))
+}
+
+func TestWriteScenarioBech32(t *testing.T) {
+	contents, err := loadExampleFile("bech32.scen.json")
+	require.Nil(t, err)
+
+	p := mjparse.NewParser(fr.NewDefaultFileResolver())
+	scenario, parseErr := p.ParseScenarioFile(contents)
+	require.Nil(t, parseErr)
+	account := scenario.Steps[0].(*mj.SetStateStep).Accounts[0]
+	require.Equal(t, "0139472eff6886771a982f3083da5d421f24c29181e63888228dc81ca60d69e1", hex.EncodeToString(account.Address.Value))
+
+	// bech32 addresses are written back as they were
+	require.Equal(t, contents, []byte(mjwrite.ScenarioToJSONString(scenario)))
+}
@@ -0,0 +1,48 @@
+package mandosjsonmodel
+
+import (
+	oj "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson"
+)
+
+// The OriginalHash methods fingerprint values by how they were written in the scenario,
+// not by the value they were interpreted to, e.g. "1000" and "0x03e8" hash differently.
+// Originals kept as plain strings hash the same as the JSON string they came from.
+
+func hashOriginalString(original string) oj.Fingerprint {
+	return oj.Hash(&oj.OJsonString{Value: original})
+}
+
+// OriginalHash yields a fingerprint of the original JSON.
+func (jb JSONBytesFromString) OriginalHash() oj.Fingerprint {
+	return hashOriginalString(jb.Original)
+}
+
+// OriginalHash yields a fingerprint of the original JSON.
+func (jb JSONBytesFromTree) OriginalHash() oj.Fingerprint {
+	return oj.Hash(jb.Original)
+}
+
+// OriginalHash yields a fingerprint of the original JSON.
+func (jbi JSONBigInt) OriginalHash() oj.Fingerprint {
+	return hashOriginalString(jbi.Original)
+}
+
+// OriginalHash yields a fingerprint of the original JSON.
+func (ju JSONUint64) OriginalHash() oj.Fingerprint {
+	return hashOriginalString(ju.Original)
+}
+
+// OriginalHash yields a fingerprint of the original JSON.
+func (jcbytes JSONCheckBytes) OriginalHash() oj.Fingerprint {
+	return oj.Hash(jcbytes.Original)
+}
+
+// OriginalHash yields a fingerprint of the original JSON.
+func (jcbi JSONCheckBigInt) OriginalHash() oj.Fingerprint {
+	return hashOriginalString(jcbi.Original)
+}
+
+// OriginalHash yields a fingerprint of the original JSON.
+func (jcu JSONCheckUint64) OriginalHash() oj.Fingerprint {
+	return hashOriginalString(jcu.Original)
+}
@@ -63,6 +63,8 @@ type JSONCheckBigInt struct {
 	Value    *big.Int
 	IsStar   bool
 	Original string
+	// OriginalIsNumber is true if the original was a plain JSON number, e.g. 1e3, rather than a string.
+	OriginalIsNumber bool
 }
 
 // JSONCheckBigIntDefault yields JSONCheckBigInt default "*" value.
@@ -95,6 +97,8 @@ type JSONCheckUint64 struct {
 	Value    uint64
 	IsStar   bool
 	Original string
+	// OriginalIsNumber is true if the original was a plain JSON number, e.g. 1e3, rather than a string.
+	OriginalIsNumber bool
 }
 
 // JSONCheckUint64Default yields JSONCheckBigInt default "*" value.
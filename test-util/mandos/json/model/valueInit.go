@@ -10,6 +10,8 @@ import (
 type JSONBytesFromString struct {
 	Value    []byte
 	Original string
+	// OriginalIsNumber is true if the original was a plain JSON number, e.g. 1e3, rather than a string.
+	OriginalIsNumber bool
 }
 
 // NewJSONBytesFromString creates a new JSONBytesFromString instance.
@@ -41,10 +43,14 @@ func (jb JSONBytesFromTree) OriginalEmpty() bool {
 type JSONBigInt struct {
 	Value    *big.Int
 	Original string
+	// OriginalIsNumber is true if the original was a plain JSON number, e.g. 1e3, rather than a string.
+	OriginalIsNumber bool
 }
 
 // JSONUint64 stores the parsed uint64 value but also the original parsed string
 type JSONUint64 struct {
 	Value    uint64
 	Original string
+	// OriginalIsNumber is true if the original was a plain JSON number, e.g. 1e3, rather than a string.
+	OriginalIsNumber bool
 }
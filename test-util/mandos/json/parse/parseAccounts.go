@@ -1,28 +1,44 @@
 package mandosjsonparse
 
 import (
-	"errors"
 	"fmt"
+	"strings"
 
 	mj "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/model"
+	vi "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/valueinterpreter"
 	oj "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson"
 )
 
-func (p *Parser) parseAccountAddress(addrRaw string) (mj.JSONBytesFromString, error) {
+func (p *Parser) parseAccountAddress(addrRaw string, pos oj.Position) (mj.JSONBytesFromString, error) {
 	if len(addrRaw) == 0 {
-		return mj.JSONBytesFromString{}, errors.New("missing account address")
+		return mj.JSONBytesFromString{}, errorAtf(pos, "missing account address")
 	}
-	addrBytes, err := p.ValueInterpreter.InterpretString(addrRaw)
-	if err == nil && len(addrBytes) != 32 {
-		return mj.JSONBytesFromString{}, errors.New("account addressis not 32 bytes in length")
+	addrBytes, err := p.interpretAccountAddress(addrRaw)
+	if err != nil {
+		return mj.JSONBytesFromString{}, errorAt(pos, err)
 	}
-	return mj.NewJSONBytesFromString(addrBytes, addrRaw), err
+	if len(addrBytes) != 32 {
+		return mj.JSONBytesFromString{}, errorAtf(pos, "account address is not 32 bytes in length")
+	}
+	return mj.NewJSONBytesFromString(addrBytes, addrRaw), nil
+}
+
+// interpretAccountAddress also accepts bech32 addresses without prefix, e.g. "erd1...".
+func (p *Parser) interpretAccountAddress(addrRaw string) ([]byte, error) {
+	if !strings.HasPrefix(addrRaw, vi.AddressHRP+"1") {
+		return p.ValueInterpreter.InterpretString(addrRaw)
+	}
+	addrBytes, err := vi.DecodeBech32Address(addrRaw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bech32 address: %w", err)
+	}
+	return addrBytes, nil
 }
 
 func (p *Parser) processAccount(acctRaw oj.OJsonObject) (*mj.Account, error) {
 	acctMap, isMap := acctRaw.(*oj.OJsonMap)
 	if !isMap {
-		return nil, errors.New("unmarshalled account object is not a map")
+		return nil, errorAtf(acctRaw.Position(), "unmarshalled account object is not a map")
 	}
 
 	acct := mj.Account{}
@@ -38,22 +54,22 @@ func (p *Parser) processAccount(acctRaw oj.OJsonObject) (*mj.Account, error) {
 		case "nonce":
 			acct.Nonce, err = p.processUint64(kvp.Value)
 			if err != nil {
-				return nil, errors.New("invalid account nonce")
+				return nil, fmt.Errorf("invalid account nonce: %w", err)
 			}
 		case "balance":
 			acct.Balance, err = p.processBigInt(kvp.Value, bigIntUnsignedBytes)
 			if err != nil {
-				return nil, errors.New("invalid account balance")
+				return nil, fmt.Errorf("invalid account balance: %w", err)
 			}
 		case "storage":
 			storageMap, storageOk := kvp.Value.(*oj.OJsonMap)
 			if !storageOk {
-				return nil, errors.New("invalid account storage")
+				return nil, errorAtf(kvp.Value.Position(), "invalid account storage")
 			}
 			for _, storageKvp := range storageMap.OrderedKV {
 				byteKey, err := p.ValueInterpreter.InterpretString(storageKvp.Key)
 				if err != nil {
-					return nil, fmt.Errorf("invalid account storage key: %w", err)
+					return nil, fmt.Errorf("invalid account storage key: %w", errorAt(storageKvp.KeyPos, err))
 				}
 				byteVal, err := p.processSubTreeAsByteArray(storageKvp.Value)
 				if err != nil {
@@ -76,7 +92,7 @@ func (p *Parser) processAccount(acctRaw oj.OJsonObject) (*mj.Account, error) {
 				return nil, fmt.Errorf("invalid asyncCallData string: %w", err)
 			}
 		default:
-			return nil, fmt.Errorf("unknown account field: %s", kvp.Key)
+			return nil, errorAtf(kvp.KeyPos, "unknown account field: %s", kvp.Key)
 		}
 	}
 
@@ -87,14 +103,14 @@ func (p *Parser) processAccountMap(acctMapRaw oj.OJsonObject) ([]*mj.Account, er
 	var accounts []*mj.Account
 	preMap, isPreMap := acctMapRaw.(*oj.OJsonMap)
 	if !isPreMap {
-		return nil, errors.New("unmarshalled account map object is not a map")
+		return nil, errorAtf(acctMapRaw.Position(), "unmarshalled account map object is not a map")
 	}
 	for _, acctKVP := range preMap.OrderedKV {
 		acct, acctErr := p.processAccount(acctKVP.Value)
 		if acctErr != nil {
 			return nil, acctErr
 		}
-		acctAddr, hexErr := p.parseAccountAddress(acctKVP.Key)
+		acctAddr, hexErr := p.parseAccountAddress(acctKVP.Key, acctKVP.KeyPos)
 		if hexErr != nil {
 			return nil, hexErr
 		}
@@ -108,7 +124,7 @@ func (p *Parser) processAccountMap(acctMapRaw oj.OJsonObject) ([]*mj.Account, er
 func (p *Parser) processCheckAccount(acctRaw oj.OJsonObject) (*mj.CheckAccount, error) {
 	acctMap, isMap := acctRaw.(*oj.OJsonMap)
 	if !isMap {
-		return nil, errors.New("unmarshalled account object is not a map")
+		return nil, errorAtf(acctRaw.Position(), "unmarshalled account object is not a map")
 	}
 
 	acct := mj.CheckAccount{
@@ -130,12 +146,12 @@ func (p *Parser) processCheckAccount(acctRaw oj.OJsonObject) (*mj.CheckAccount,
 		case "nonce":
 			acct.Nonce, err = p.processCheckUint64(kvp.Value)
 			if err != nil {
-				return nil, errors.New("invalid account nonce")
+				return nil, fmt.Errorf("invalid account nonce: %w", err)
 			}
 		case "balance":
 			acct.Balance, err = p.processCheckBigInt(kvp.Value, bigIntUnsignedBytes)
 			if err != nil {
-				return nil, errors.New("invalid account balance")
+				return nil, fmt.Errorf("invalid account balance: %w", err)
 			}
 		case "storage":
 			acct.IgnoreStorage = IsStar(kvp.Value)
@@ -143,12 +159,12 @@ func (p *Parser) processCheckAccount(acctRaw oj.OJsonObject) (*mj.CheckAccount,
 				// TODO: convert to a more permissive format
 				storageMap, storageOk := kvp.Value.(*oj.OJsonMap)
 				if !storageOk {
-					return nil, errors.New("invalid account storage")
+					return nil, errorAtf(kvp.Value.Position(), "invalid account storage")
 				}
 				for _, storageKvp := range storageMap.OrderedKV {
 					byteKey, err := p.ValueInterpreter.InterpretString(storageKvp.Key)
 					if err != nil {
-						return nil, fmt.Errorf("invalid account storage key: %w", err)
+						return nil, fmt.Errorf("invalid account storage key: %w", errorAt(storageKvp.KeyPos, err))
 					}
 					byteVal, err := p.processSubTreeAsByteArray(storageKvp.Value)
 					if err != nil {
@@ -172,7 +188,7 @@ func (p *Parser) processCheckAccount(acctRaw oj.OJsonObject) (*mj.CheckAccount,
 				return nil, fmt.Errorf("invalid asyncCallData: %w", err)
 			}
 		default:
-			return nil, fmt.Errorf("unknown account field: %s", kvp.Key)
+			return nil, errorAtf(kvp.KeyPos, "unknown account field: %s", kvp.Key)
 		}
 	}
 
@@ -187,7 +203,7 @@ func (p *Parser) processCheckAccountMap(acctMapRaw oj.OJsonObject) (*mj.CheckAcc
 
 	preMap, isPreMap := acctMapRaw.(*oj.OJsonMap)
 	if !isPreMap {
-		return nil, errors.New("unmarshalled check account map object is not a map")
+		return nil, errorAtf(acctMapRaw.Position(), "unmarshalled check account map object is not a map")
 	}
 	for _, acctKVP := range preMap.OrderedKV {
 		if acctKVP.Key == "+" {
@@ -197,7 +213,7 @@ func (p *Parser) processCheckAccountMap(acctMapRaw oj.OJsonObject) (*mj.CheckAcc
 			if acctErr != nil {
 				return nil, acctErr
 			}
-			acctAddr, hexErr := p.parseAccountAddress(acctKVP.Key)
+			acctAddr, hexErr := p.parseAccountAddress(acctKVP.Key, acctKVP.KeyPos)
 			if hexErr != nil {
 				return nil, hexErr
 			}
@@ -1,7 +1,6 @@
 package mandosjsonparse
 
 import (
-	"errors"
 	"fmt"
 
 	mj "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/model"
@@ -11,7 +10,7 @@ import (
 func (p *Parser) processBlock(blockRaw oj.OJsonObject) (*mj.Block, error) {
 	blockMap, isMap := blockRaw.(*oj.OJsonMap)
 	if !isMap {
-		return nil, errors.New("unmarshalled block object is not a map")
+		return nil, errorAtf(blockRaw.Position(), "unmarshalled block object is not a map")
 	}
 	bl := mj.Block{}
 
@@ -20,7 +19,7 @@ func (p *Parser) processBlock(blockRaw oj.OJsonObject) (*mj.Block, error) {
 		case "results":
 			resultsRaw, resultsOk := kvp.Value.(*oj.OJsonList)
 			if !resultsOk {
-				return nil, errors.New("unmarshalled block results object is not a list")
+				return nil, errorAtf(kvp.Value.Position(), "unmarshalled block results object is not a list")
 			}
 			for _, resRaw := range resultsRaw.AsList() {
 				blr, blrErr := p.processTxExpectedResult(resRaw)
@@ -32,7 +31,7 @@ func (p *Parser) processBlock(blockRaw oj.OJsonObject) (*mj.Block, error) {
 		case "transactions":
 			transactionsRaw, transactionsOk := kvp.Value.(*oj.OJsonList)
 			if !transactionsOk {
-				return nil, errors.New("unmarshalled block transactions object is not a list")
+				return nil, errorAtf(kvp.Value.Position(), "unmarshalled block transactions object is not a list")
 			}
 			for _, trRaw := range transactionsRaw.AsList() {
 				var txType mj.TransactionType
@@ -58,12 +57,12 @@ func (p *Parser) processBlock(blockRaw oj.OJsonObject) (*mj.Block, error) {
 			}
 			bl.BlockHeader = blh
 		default:
-			return nil, fmt.Errorf("unknown block field: %s", kvp.Key)
+			return nil, errorAtf(kvp.KeyPos, "unknown block field: %s", kvp.Key)
 		}
 	}
 
 	if len(bl.Results) != len(bl.Transactions) {
-		return nil, errors.New("mismatched number of blocks and transactions")
+		return nil, errorAtf(blockMap.Position(), "mismatched number of blocks and transactions")
 	}
 
 	return &bl, nil
@@ -73,7 +72,7 @@ func (p *Parser) processBlock(blockRaw oj.OJsonObject) (*mj.Block, error) {
 func (p *Parser) txIsCreate(txRaw oj.OJsonObject) (bool, error) {
 	txRawMap, isMap := txRaw.(*oj.OJsonMap)
 	if !isMap {
-		return false, errors.New("unmarshalled block transaction is not a map")
+		return false, errorAtf(txRaw.Position(), "unmarshalled block transaction is not a map")
 	}
 	for _, kvp := range txRawMap.OrderedKV {
 		switch kvp.Key {
@@ -88,10 +87,10 @@ func (p *Parser) txIsCreate(txRaw oj.OJsonObject) (bool, error) {
 	return false, nil
 }
 
-func (p *Parser) processBlockHeader(blhRaw interface{}) (*mj.BlockHeader, error) {
+func (p *Parser) processBlockHeader(blhRaw oj.OJsonObject) (*mj.BlockHeader, error) {
 	blhMap, isMap := blhRaw.(*oj.OJsonMap)
 	if !isMap {
-		return nil, errors.New("unmarshalled block header is not a map")
+		return nil, errorAtf(blhRaw.Position(), "unmarshalled block header is not a map")
 	}
 
 	blh := mj.BlockHeader{}
@@ -125,7 +124,7 @@ func (p *Parser) processBlockHeader(blhRaw interface{}) (*mj.BlockHeader, error)
 				return nil, fmt.Errorf("invalid block header coinbase: %w", err)
 			}
 		default:
-			return nil, fmt.Errorf("unknown block header field: %s", kvp.Key)
+			return nil, errorAtf(kvp.KeyPos, "unknown block header field: %s", kvp.Key)
 		}
 	}
 
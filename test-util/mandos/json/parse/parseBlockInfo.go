@@ -1,7 +1,6 @@
 package mandosjsonparse
 
 import (
-	"errors"
 	"fmt"
 
 	mj "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/model"
@@ -11,7 +10,7 @@ import (
 func (p *Parser) processBlockInfo(blockInfoRaw oj.OJsonObject) (*mj.BlockInfo, error) {
 	blockMap, isMap := blockInfoRaw.(*oj.OJsonMap)
 	if !isMap {
-		return nil, errors.New("unmarshalled block info object is not a map")
+		return nil, errorAtf(blockInfoRaw.Position(), "unmarshalled block info object is not a map")
 	}
 	blockInfo := &mj.BlockInfo{}
 	var err error
@@ -39,7 +38,7 @@ func (p *Parser) processBlockInfo(blockInfoRaw oj.OJsonObject) (*mj.BlockInfo, e
 				return nil, fmt.Errorf("error parsing blockEpoch: %w", err)
 			}
 		default:
-			return nil, fmt.Errorf("unknown block info field: %s", kvp.Key)
+			return nil, errorAtf(kvp.KeyPos, "unknown block info field: %s", kvp.Key)
 		}
 	}
 
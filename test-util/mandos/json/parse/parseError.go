@@ -0,0 +1,54 @@
+package mandosjsonparse
+
+import (
+	"errors"
+	"fmt"
+
+	oj "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson"
+)
+
+// fieldError marks the JSON object that caused a parse error.
+// It leaves the error message unchanged, the position only gets printed once, by withPosition.
+type fieldError struct {
+	pos oj.Position
+	err error
+}
+
+func (e *fieldError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fieldError) Unwrap() error {
+	return e.err
+}
+
+func errorAt(pos oj.Position, err error) error {
+	return &fieldError{pos: pos, err: err}
+}
+
+func errorAtf(pos oj.Position, format string, args ...interface{}) error {
+	return errorAt(pos, fmt.Errorf(format, args...))
+}
+
+// withPosition prefixes the error message with the position of the innermost JSON object that caused it.
+// The result is an *oj.PositionError whenever a position is known.
+func withPosition(err error) error {
+	if err == nil {
+		return nil
+	}
+	var syntaxErr *oj.PositionError
+	if errors.As(err, &syntaxErr) {
+		return err
+	}
+
+	var innermost *fieldError
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if fe, isFieldErr := e.(*fieldError); isFieldErr {
+			innermost = fe
+		}
+	}
+	if innermost == nil || !innermost.pos.IsValid() {
+		return err
+	}
+	return oj.ErrorAt(innermost.pos, err)
+}
@@ -1,16 +1,14 @@
 package mandosjsonparse
 
 import (
-	"errors"
-
 	mj "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/model"
 	oj "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson"
 )
 
-func (p *Parser) processStringList(obj interface{}) ([]string, error) {
+func (p *Parser) processStringList(obj oj.OJsonObject) ([]string, error) {
 	listRaw, listOk := obj.(*oj.OJsonList)
 	if !listOk {
-		return nil, errors.New("not a JSON list")
+		return nil, errorAtf(obj.Position(), "not a JSON list")
 	}
 	var result []string
 	for _, elemRaw := range listRaw.AsList() {
@@ -23,10 +21,10 @@ func (p *Parser) processStringList(obj interface{}) ([]string, error) {
 	return result, nil
 }
 
-func (p *Parser) parseByteArrayList(obj interface{}) ([]mj.JSONBytesFromString, error) {
+func (p *Parser) parseByteArrayList(obj oj.OJsonObject) ([]mj.JSONBytesFromString, error) {
 	listRaw, listOk := obj.(*oj.OJsonList)
 	if !listOk {
-		return nil, errors.New("not a JSON list")
+		return nil, errorAtf(obj.Position(), "not a JSON list")
 	}
 	var result []mj.JSONBytesFromString
 	for _, elemRaw := range listRaw.AsList() {
@@ -39,10 +37,10 @@ func (p *Parser) parseByteArrayList(obj interface{}) ([]mj.JSONBytesFromString,
 	return result, nil
 }
 
-func (p *Parser) parseSubTreeList(obj interface{}) ([]mj.JSONBytesFromTree, error) {
+func (p *Parser) parseSubTreeList(obj oj.OJsonObject) ([]mj.JSONBytesFromTree, error) {
 	listRaw, listOk := obj.(*oj.OJsonList)
 	if !listOk {
-		return nil, errors.New("not a JSON list")
+		return nil, errorAtf(obj.Position(), "not a JSON list")
 	}
 	var result []mj.JSONBytesFromTree
 	for _, elemRaw := range listRaw.AsList() {
@@ -55,10 +53,10 @@ func (p *Parser) parseSubTreeList(obj interface{}) ([]mj.JSONBytesFromTree, erro
 	return result, nil
 }
 
-func (p *Parser) parseCheckBytesList(obj interface{}) ([]mj.JSONCheckBytes, error) {
+func (p *Parser) parseCheckBytesList(obj oj.OJsonObject) ([]mj.JSONCheckBytes, error) {
 	listRaw, listOk := obj.(*oj.OJsonList)
 	if !listOk {
-		return nil, errors.New("not a JSON list")
+		return nil, errorAtf(obj.Position(), "not a JSON list")
 	}
 	var result []mj.JSONCheckBytes
 	for _, elemRaw := range listRaw.AsList() {
@@ -1,7 +1,6 @@
 package mandosjsonparse
 
 import (
-	"errors"
 	"fmt"
 
 	mj "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/model"
@@ -11,14 +10,14 @@ import (
 func (p *Parser) processLogList(logsRaw oj.OJsonObject) ([]*mj.LogEntry, error) {
 	logList, isList := logsRaw.(*oj.OJsonList)
 	if !isList {
-		return nil, errors.New("unmarshalled logs list is not a list")
+		return nil, errorAtf(logsRaw.Position(), "unmarshalled logs list is not a list")
 	}
 	var logEntries []*mj.LogEntry
 	var err error
 	for _, logRaw := range logList.AsList() {
 		logMap, isMap := logRaw.(*oj.OJsonMap)
 		if !isMap {
-			return nil, errors.New("unmarshalled log entry is not a map")
+			return nil, errorAtf(logRaw.Position(), "unmarshalled log entry is not a map")
 		}
 		logEntry := mj.LogEntry{}
 		for _, kvp := range logMap.OrderedKV {
@@ -28,7 +27,7 @@ func (p *Parser) processLogList(logsRaw oj.OJsonObject) ([]*mj.LogEntry, error)
 				if err != nil {
 					return nil, fmt.Errorf("unmarshalled log entry address is not a json string: %w", err)
 				}
-				logEntry.Address, err = p.parseAccountAddress(accountStr)
+				logEntry.Address, err = p.parseAccountAddress(accountStr, kvp.Value.Position())
 				if err != nil {
 					return nil, err
 				}
@@ -40,10 +39,10 @@ func (p *Parser) processLogList(logsRaw oj.OJsonObject) ([]*mj.LogEntry, error)
 				var identifierValue []byte
 				identifierValue, err = p.ValueInterpreter.InterpretString(strVal)
 				if err != nil {
-					return nil, fmt.Errorf("invalid log identifier: %w", err)
+					return nil, fmt.Errorf("invalid log identifier: %w", errorAt(kvp.Value.Position(), err))
 				}
 				if len(identifierValue) != 32 {
-					return nil, fmt.Errorf("invalid log identifier - should be 32 bytes in length")
+					return nil, errorAtf(kvp.Value.Position(), "invalid log identifier - should be 32 bytes in length")
 				}
 				logEntry.Identifier = mj.NewJSONBytesFromString(identifierValue, strVal)
 			case "topics":
@@ -57,7 +56,7 @@ func (p *Parser) processLogList(logsRaw oj.OJsonObject) ([]*mj.LogEntry, error)
 					return nil, fmt.Errorf("cannot parse log entry data: %w", err)
 				}
 			default:
-				return nil, fmt.Errorf("unknown log field: %s", kvp.Key)
+				return nil, errorAtf(kvp.KeyPos, "unknown log field: %s", kvp.Key)
 			}
 		}
 		logEntries = append(logEntries, &logEntry)
@@ -1,7 +1,6 @@
 package mandosjsonparse
 
 import (
-	"errors"
 	"fmt"
 
 	mj "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/model"
@@ -11,14 +10,14 @@ import (
 func (p *Parser) processNewAddressMocks(namsRaw oj.OJsonObject) ([]*mj.NewAddressMock, error) {
 	namList, isList := namsRaw.(*oj.OJsonList)
 	if !isList {
-		return nil, errors.New("newAddresses list is not a list")
+		return nil, errorAtf(namsRaw.Position(), "newAddresses list is not a list")
 	}
 	var namEntries []*mj.NewAddressMock
 	var err error
 	for _, namRaw := range namList.AsList() {
 		namMap, isMap := namRaw.(*oj.OJsonMap)
 		if !isMap {
-			return nil, errors.New("new address mock entry is not a map")
+			return nil, errorAtf(namRaw.Position(), "new address mock entry is not a map")
 		}
 		namEntry := mj.NewAddressMock{}
 		for _, kvp := range namMap.OrderedKV {
@@ -28,26 +27,26 @@ func (p *Parser) processNewAddressMocks(namsRaw oj.OJsonObject) ([]*mj.NewAddres
 				if err != nil {
 					return nil, fmt.Errorf("creatorAddress is not a json string: %w", err)
 				}
-				namEntry.CreatorAddress, err = p.parseAccountAddress(caStr)
+				namEntry.CreatorAddress, err = p.parseAccountAddress(caStr, kvp.Value.Position())
 				if err != nil {
 					return nil, err
 				}
 			case "creatorNonce":
 				namEntry.CreatorNonce, err = p.processUint64(kvp.Value)
 				if err != nil {
-					return nil, errors.New("invalid creatorNonce")
+					return nil, fmt.Errorf("invalid creatorNonce: %w", err)
 				}
 			case "newAddress":
 				naStr, err := p.parseString(kvp.Value)
 				if err != nil {
 					return nil, fmt.Errorf("newAddress is not a json string: %w", err)
 				}
-				namEntry.NewAddress, err = p.parseAccountAddress(naStr)
+				namEntry.NewAddress, err = p.parseAccountAddress(naStr, kvp.Value.Position())
 				if err != nil {
 					return nil, err
 				}
 			default:
-				return nil, fmt.Errorf("unknown nam field: %s", kvp.Key)
+				return nil, errorAtf(kvp.KeyPos, "unknown nam field: %s", kvp.Key)
 			}
 		}
 		namEntries = append(namEntries, &namEntry)
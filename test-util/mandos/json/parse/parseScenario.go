@@ -1,7 +1,6 @@
 package mandosjsonparse
 
 import (
-	"errors"
 	"fmt"
 
 	mj "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/model"
@@ -10,14 +9,28 @@ import (
 
 // ParseScenarioFile converts a scenario json string to scenario object representation
 func (p *Parser) ParseScenarioFile(jsonString []byte) (*mj.Scenario, error) {
-	jobj, err := oj.ParseOrderedJSON(jsonString)
+	jobj, err := oj.ParseOrderedJSONWithOptions(jsonString, p.JSONOptions)
 	if err != nil {
 		return nil, err
 	}
+	return p.processScenario(jobj)
+}
+
+// ParseScenarioYAMLFile converts a scenario written in YAML to scenario object representation.
+// The YAML is converted to the same tree as the JSON, so both formats accept exactly the same scenarios.
+func (p *Parser) ParseScenarioYAMLFile(yamlString []byte) (*mj.Scenario, error) {
+	jobj, err := oj.ParseYAMLWithOptions(yamlString, p.JSONOptions)
+	if err != nil {
+		return nil, err
+	}
+	return p.processScenario(jobj)
+}
 
+func (p *Parser) processScenario(jobj oj.OJsonObject) (*mj.Scenario, error) {
+	var err error
 	topMap, isMap := jobj.(*oj.OJsonMap)
 	if !isMap {
-		return nil, errors.New("unmarshalled test top level object is not a map")
+		return nil, withPosition(errorAtf(jobj.Position(), "unmarshalled test top level object is not a map"))
 	}
 
 	scenario := &mj.Scenario{
@@ -28,35 +41,35 @@ func (p *Parser) ParseScenarioFile(jsonString []byte) (*mj.Scenario, error) {
 		case "name":
 			scenario.Name, err = p.parseString(kvp.Value)
 			if err != nil {
-				return nil, fmt.Errorf("bad scenario name: %w", err)
+				return nil, withPosition(fmt.Errorf("bad scenario name: %w", err))
 			}
 		case "comment":
 			scenario.Comment, err = p.parseString(kvp.Value)
 			if err != nil {
-				return nil, fmt.Errorf("bad scenario comment: %w", err)
+				return nil, withPosition(fmt.Errorf("bad scenario comment: %w", err))
 			}
 		case "checkGas":
 			checkGasOJ, isBool := kvp.Value.(*oj.OJsonBool)
 			if !isBool {
-				return nil, errors.New("scenario checkGas flag is not boolean")
+				return nil, withPosition(errorAtf(kvp.Value.Position(), "scenario checkGas flag is not boolean"))
 			}
-			scenario.CheckGas = bool(*checkGasOJ)
+			scenario.CheckGas = checkGasOJ.Value
 		case "steps":
 			scenario.Steps, err = p.processScenarioStepList(kvp.Value)
 			if err != nil {
-				return nil, fmt.Errorf("error processing steps: %w", err)
+				return nil, withPosition(fmt.Errorf("error processing steps: %w", err))
 			}
 		default:
-			return nil, fmt.Errorf("unknown step field: %s", kvp.Key)
+			return nil, withPosition(errorAtf(kvp.KeyPos, "unknown scenario field: %s", kvp.Key))
 		}
 	}
 	return scenario, nil
 }
 
-func (p *Parser) processScenarioStepList(obj interface{}) ([]mj.Step, error) {
+func (p *Parser) processScenarioStepList(obj oj.OJsonObject) ([]mj.Step, error) {
 	listRaw, listOk := obj.(*oj.OJsonList)
 	if !listOk {
-		return nil, errors.New("steps not a JSON list")
+		return nil, errorAtf(obj.Position(), "steps not a JSON list")
 	}
 	var stepList []mj.Step
 	for _, elemRaw := range listRaw.AsList() {
@@ -72,18 +85,22 @@ func (p *Parser) processScenarioStepList(obj interface{}) ([]mj.Step, error) {
 // ParseScenarioStep parses a single scenario step, instead of an entire file.
 // Handy for tests, where step snippets can be embedded in code.
 func (p *Parser) ParseScenarioStep(jsonSnippet string) (mj.Step, error) {
-	jobj, err := oj.ParseOrderedJSON([]byte(jsonSnippet))
+	jobj, err := oj.ParseOrderedJSONWithOptions([]byte(jsonSnippet), p.JSONOptions)
 	if err != nil {
 		return nil, err
 	}
 
-	return p.processScenarioStep(jobj)
+	step, err := p.processScenarioStep(jobj)
+	if err != nil {
+		return nil, withPosition(err)
+	}
+	return step, nil
 }
 
 func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (mj.Step, error) {
 	stepMap, isStepMap := stepObj.(*oj.OJsonMap)
 	if !isStepMap {
-		return nil, errors.New("unmarshalled step object is not a map")
+		return nil, errorAtf(stepObj.Position(), "unmarshalled step object is not a map")
 	}
 
 	var err error
@@ -99,7 +116,7 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (mj.Step, error) {
 
 	switch stepTypeStr {
 	case "":
-		return nil, errors.New("no step type field provided")
+		return nil, errorAtf(stepMap.Position(), "no step type field provided")
 	case mj.StepNameExternalSteps:
 		step := &mj.ExternalStepsStep{}
 		for _, kvp := range stepMap.OrderedKV {
@@ -111,7 +128,7 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (mj.Step, error) {
 					return nil, fmt.Errorf("bad externalSteps path: %w", err)
 				}
 			default:
-				return nil, fmt.Errorf("invalid externalSteps field: %s", kvp.Key)
+				return nil, errorAtf(kvp.KeyPos, "invalid externalSteps field: %s", kvp.Key)
 			}
 		}
 		return step, nil
@@ -151,7 +168,7 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (mj.Step, error) {
 					return nil, fmt.Errorf("error parsing block hashes: %w", err)
 				}
 			default:
-				return nil, fmt.Errorf("invalid set state field: %s", kvp.Key)
+				return nil, errorAtf(kvp.KeyPos, "invalid set state field: %s", kvp.Key)
 			}
 		}
 		return step, nil
@@ -171,7 +188,7 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (mj.Step, error) {
 					return nil, fmt.Errorf("cannot parse check state step: %w", err)
 				}
 			default:
-				return nil, fmt.Errorf("invalid check state field: %s", kvp.Key)
+				return nil, errorAtf(kvp.KeyPos, "invalid check state field: %s", kvp.Key)
 			}
 		}
 		return step, nil
@@ -186,7 +203,7 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (mj.Step, error) {
 					return nil, fmt.Errorf("bad check state step comment: %w", err)
 				}
 			default:
-				return nil, fmt.Errorf("invalid check state field: %s", kvp.Key)
+				return nil, errorAtf(kvp.KeyPos, "invalid check state field: %s", kvp.Key)
 			}
 		}
 		return step, nil
@@ -199,7 +216,7 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (mj.Step, error) {
 	case mj.StepNameValidatorReward:
 		return p.parseTxStep(mj.ValidatorReward, stepMap)
 	default:
-		return nil, fmt.Errorf("unknown step type: %s", stepTypeStr)
+		return nil, errorAtf(stepMap.Position(), "unknown step type: %s", stepTypeStr)
 	}
 }
 
@@ -226,14 +243,14 @@ func (p *Parser) parseTxStep(txType mj.TransactionType, stepMap *oj.OJsonMap) (*
 			}
 		case "expect":
 			if !step.Tx.Type.IsSmartContractTx() {
-				return nil, fmt.Errorf("no expected result allowed for step of type %s", step.StepTypeName())
+				return nil, errorAtf(kvp.KeyPos, "no expected result allowed for step of type %s", step.StepTypeName())
 			}
 			step.ExpectedResult, err = p.processTxExpectedResult(kvp.Value)
 			if err != nil {
 				return nil, fmt.Errorf("cannot parse tx expected result: %w", err)
 			}
 		default:
-			return nil, fmt.Errorf("invalid tx step field: %s", kvp.Key)
+			return nil, errorAtf(kvp.KeyPos, "invalid tx step field: %s", kvp.Key)
 		}
 	}
 	return step, nil
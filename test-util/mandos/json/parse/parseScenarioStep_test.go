@@ -1,8 +1,11 @@
 package mandosjsonparse
 
 import (
+	"strings"
 	"testing"
 
+	mj "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/model"
+	oj "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson"
 	"github.com/stretchr/testify/require"
 )
 
@@ -51,3 +54,81 @@ func TestParseScenario(t *testing.T) {
 	require.NotNil(t, step)
 	require.Equal(t, "scCall", step.StepTypeName())
 }
+
+func TestParseScenarioStepErrorPosition(t *testing.T) {
+	snippet := `{
+	"step": "scCall",
+	"tx": {
+		"from": "0xa94f5374fce5edbc8e2a8697c15331677e6ebf0b000000000000000000000000",
+		"to": "0x1000000000000000000000000000000000000000000000000000000000000000",
+		"value": "0x00",
+		"functionName": "someFunctionName"
+	}
+}`
+
+	p := Parser{}
+	_, parseErr := p.ParseScenarioStep(snippet)
+	require.NotNil(t, parseErr)
+	posErr, isPosErr := parseErr.(*oj.PositionError)
+	require.True(t, isPosErr)
+	require.Equal(t, 7, posErr.Pos.Line)
+	require.Equal(t, 3, posErr.Pos.Column)
+	require.Equal(t, "7:3: cannot parse tx step transaction: unknown field in transaction: functionName", parseErr.Error())
+
+	_, parseErr = p.ParseScenarioStep(`{"step": "scCall", "tx": {"value": "0x00" "gasLimit": "0"}}`)
+	require.NotNil(t, parseErr)
+	require.Equal(t, "1:43: unexpected character '\"' in map, ',' or '}' expected", parseErr.Error())
+
+	_, parseErr = p.ParseScenarioStep(`{"step": "scCall", "tx": {"value": "0x00", "value": "0x01"}}`)
+	require.NotNil(t, parseErr)
+	require.Equal(t, "1:44: duplicate key \"value\", first defined at 1:27", parseErr.Error())
+}
+
+func TestParseScenarioStepJSONC(t *testing.T) {
+	snippet := `{
+	// comments are allowed in JSONC
+	"step": "transfer",
+	"tx": {
+		"from": "''sender__________________________", /* block comment */
+		"to": "''receiver________________________",
+		"value": "0x00",
+	},
+}`
+
+	p := Parser{}
+	_, parseErr := p.ParseScenarioStep(snippet)
+	require.NotNil(t, parseErr)
+
+	p.JSONOptions.Dialect = oj.DialectJSONC
+	step, parseErr := p.ParseScenarioStep(snippet)
+	require.Nil(t, parseErr)
+	require.Equal(t, "transfer", step.StepTypeName())
+}
+
+func TestParseScenarioStepLimits(t *testing.T) {
+	p := NewParser(nil)
+	deep := `{"step": "scCall", "comment": ` + strings.Repeat("[", 100) + strings.Repeat("]", 100) + `}`
+	_, parseErr := p.ParseScenarioStep(deep)
+	require.NotNil(t, parseErr)
+	require.Equal(t, "1:94: maximum nesting depth of 64 exceeded", parseErr.Error())
+}
+
+func TestParseScenarioYAML(t *testing.T) {
+	p := NewParser(nil)
+	scenario, parseErr := p.ParseScenarioYAMLFile([]byte(`
+name: yaml example
+steps:
+  - step: transfer
+    tx:
+      from: "''sender__________________________"
+      to: "''receiver________________________"
+      value: 0x10 # not quoted, still interpreted by mandos
+`))
+	require.Nil(t, parseErr)
+	require.Equal(t, "yaml example", scenario.Name)
+	require.Equal(t, "transfer", scenario.Steps[0].StepTypeName())
+	require.Equal(t, int64(16), scenario.Steps[0].(*mj.TxStep).Tx.Value.Value.Int64())
+
+	_, parseErr = p.ParseScenarioYAMLFile([]byte("name: x\nstep: []\n"))
+	require.Equal(t, "2:1: unknown scenario field: step", parseErr.Error())
+}
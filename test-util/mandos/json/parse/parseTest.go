@@ -1,7 +1,6 @@
 package mandosjsonparse
 
 import (
-	"errors"
 	"fmt"
 
 	mj "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/model"
@@ -11,21 +10,21 @@ import (
 // ParseTestFile converts json string to object representation
 func (p *Parser) ParseTestFile(jsonString []byte) ([]*mj.Test, error) {
 
-	jobj, err := oj.ParseOrderedJSON(jsonString)
+	jobj, err := oj.ParseOrderedJSONWithOptions(jsonString, p.JSONOptions)
 	if err != nil {
 		return nil, err
 	}
 
 	topMap, isMap := jobj.(*oj.OJsonMap)
 	if !isMap {
-		return nil, errors.New("unmarshalled test top level object is not a map")
+		return nil, withPosition(errorAtf(jobj.Position(), "unmarshalled test top level object is not a map"))
 	}
 
 	var top []*mj.Test
 	for _, kvp := range topMap.OrderedKV {
 		t, tErr := p.processTest(kvp.Value)
 		if tErr != nil {
-			return nil, tErr
+			return nil, withPosition(tErr)
 		}
 		t.TestName = kvp.Key
 		top = append(top, t)
@@ -36,7 +35,7 @@ func (p *Parser) ParseTestFile(jsonString []byte) ([]*mj.Test, error) {
 func (p *Parser) processTest(testObj oj.OJsonObject) (*mj.Test, error) {
 	testMap, isTestMap := testObj.(*oj.OJsonMap)
 	if !isTestMap {
-		return nil, errors.New("unmarshalled test object is not a map")
+		return nil, errorAtf(testObj.Position(), "unmarshalled test object is not a map")
 	}
 	test := mj.Test{CheckGas: true}
 
@@ -46,9 +45,9 @@ func (p *Parser) processTest(testObj oj.OJsonObject) (*mj.Test, error) {
 		case "checkGas":
 			checkGasOJ, isBool := kvp.Value.(*oj.OJsonBool)
 			if !isBool {
-				return nil, errors.New("unmarshalled test checkGas flag is not boolean")
+				return nil, errorAtf(kvp.Value.Position(), "unmarshalled test checkGas flag is not boolean")
 			}
-			test.CheckGas = bool(*checkGasOJ)
+			test.CheckGas = checkGasOJ.Value
 		case "pre":
 			test.Pre, err = p.processAccountMap(kvp.Value)
 			if err != nil {
@@ -57,7 +56,7 @@ func (p *Parser) processTest(testObj oj.OJsonObject) (*mj.Test, error) {
 		case "blocks":
 			blocksRaw, blocksOk := kvp.Value.(*oj.OJsonList)
 			if !blocksOk {
-				return nil, errors.New("unmarshalled blocks object is not a list")
+				return nil, errorAtf(kvp.Value.Position(), "unmarshalled blocks object is not a list")
 			}
 			for _, blRaw := range blocksRaw.AsList() {
 				bl, blErr := p.processBlock(blRaw)
@@ -83,7 +82,7 @@ func (p *Parser) processTest(testObj oj.OJsonObject) (*mj.Test, error) {
 				return nil, fmt.Errorf("cannot parse postState: %w", err)
 			}
 		default:
-			return nil, fmt.Errorf("unknown test: %s", kvp.Key)
+			return nil, errorAtf(kvp.KeyPos, "unknown test field: %s", kvp.Key)
 		}
 	}
 
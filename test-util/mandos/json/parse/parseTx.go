@@ -1,7 +1,6 @@
 package mandosjsonparse
 
 import (
-	"errors"
 	"fmt"
 
 	mj "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/model"
@@ -11,7 +10,7 @@ import (
 func (p *Parser) processTx(txType mj.TransactionType, blrRaw oj.OJsonObject) (*mj.Transaction, error) {
 	bltMap, isMap := blrRaw.(*oj.OJsonMap)
 	if !isMap {
-		return nil, errors.New("unmarshalled block transaction is not a map")
+		return nil, errorAtf(blrRaw.Position(), "unmarshalled block transaction is not a map")
 	}
 
 	blt := mj.Transaction{Type: txType}
@@ -26,14 +25,14 @@ func (p *Parser) processTx(txType mj.TransactionType, blrRaw oj.OJsonObject) (*m
 			}
 		case "from":
 			if !txType.HasSender() {
-				return nil, errors.New("`from` not allowed in transaction, it is always the zero address")
+				return nil, errorAtf(kvp.KeyPos, "`from` not allowed in transaction, it is always the zero address")
 			}
 			fromStr, err := p.parseString(kvp.Value)
 			if err != nil {
 				return nil, fmt.Errorf("invalid block transaction from: %w", err)
 			}
 			var fromErr error
-			blt.From, fromErr = p.parseAccountAddress(fromStr)
+			blt.From, fromErr = p.parseAccountAddress(fromStr, kvp.Value.Position())
 			if fromErr != nil {
 				return nil, fromErr
 			}
@@ -46,10 +45,10 @@ func (p *Parser) processTx(txType mj.TransactionType, blrRaw oj.OJsonObject) (*m
 
 			if txType == mj.ScDeploy {
 				if len(toStr) > 0 {
-					return nil, errors.New("transaction to field not allowed for scDeploy transactions")
+					return nil, errorAtf(kvp.KeyPos, "transaction to field not allowed for scDeploy transactions")
 				}
 			} else {
-				blt.To, err = p.parseAccountAddress(toStr)
+				blt.To, err = p.parseAccountAddress(toStr, kvp.Value.Position())
 				if err != nil {
 					return nil, err
 				}
@@ -60,10 +59,10 @@ func (p *Parser) processTx(txType mj.TransactionType, blrRaw oj.OJsonObject) (*m
 				return nil, fmt.Errorf("invalid block transaction function: %w", err)
 			}
 			if txType == mj.ScDeploy && len(blt.Function) > 0 {
-				return nil, errors.New("transaction function field not allowed for scDeploy transactions")
+				return nil, errorAtf(kvp.KeyPos, "transaction function field not allowed for scDeploy transactions")
 			}
 			if txType == mj.Transfer && len(blt.Function) > 0 {
-				return nil, errors.New("transaction function field not allowed for transfer transactions")
+				return nil, errorAtf(kvp.KeyPos, "transaction function field not allowed for transfer transactions")
 			}
 		case "value":
 			blt.Value, err = p.processBigInt(kvp.Value, bigIntUnsignedBytes)
@@ -76,7 +75,7 @@ func (p *Parser) processTx(txType mj.TransactionType, blrRaw oj.OJsonObject) (*m
 				return nil, fmt.Errorf("invalid block transaction arguments: %w", err)
 			}
 			if txType == mj.Transfer && len(blt.Arguments) > 0 {
-				return nil, errors.New("function arguments not allowed for transfer transactions")
+				return nil, errorAtf(kvp.KeyPos, "function arguments not allowed for transfer transactions")
 			}
 		case "contractCode":
 			blt.Code, err = p.processStringAsByteArray(kvp.Value)
@@ -84,7 +83,7 @@ func (p *Parser) processTx(txType mj.TransactionType, blrRaw oj.OJsonObject) (*m
 				return nil, fmt.Errorf("invalid block transaction contract code: %w", err)
 			}
 			if txType != mj.ScDeploy && len(blt.Code.Value) > 0 {
-				return nil, errors.New("transaction contractCode field only allowed int scDeploy transactions")
+				return nil, errorAtf(kvp.KeyPos, "transaction contractCode field only allowed int scDeploy transactions")
 			}
 		case "gasPrice":
 			blt.GasPrice, err = p.processUint64(kvp.Value)
@@ -97,7 +96,7 @@ func (p *Parser) processTx(txType mj.TransactionType, blrRaw oj.OJsonObject) (*m
 				return nil, fmt.Errorf("invalid block transaction gasLimit: %w", err)
 			}
 		default:
-			return nil, fmt.Errorf("unknown field in transaction: %w", err)
+			return nil, errorAtf(kvp.KeyPos, "unknown field in transaction: %s", kvp.Key)
 		}
 	}
 
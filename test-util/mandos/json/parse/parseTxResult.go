@@ -1,7 +1,6 @@
 package mandosjsonparse
 
 import (
-	"errors"
 	"fmt"
 
 	mj "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/model"
@@ -11,7 +10,7 @@ import (
 func (p *Parser) processTxExpectedResult(blrRaw oj.OJsonObject) (*mj.TransactionResult, error) {
 	blrMap, isMap := blrRaw.(*oj.OJsonMap)
 	if !isMap {
-		return nil, errors.New("unmarshalled block result is not a map")
+		return nil, errorAtf(blrRaw.Position(), "unmarshalled block result is not a map")
 	}
 
 	blr := mj.TransactionResult{
@@ -63,7 +62,7 @@ func (p *Parser) processTxExpectedResult(blrRaw oj.OJsonObject) (*mj.Transaction
 				return nil, fmt.Errorf("invalid block result refund: %w", err)
 			}
 		default:
-			return nil, fmt.Errorf("unknown tx result field: %s", kvp.Key)
+			return nil, errorAtf(kvp.KeyPos, "unknown tx result field: %s", kvp.Key)
 		}
 	}
 
@@ -30,22 +30,28 @@ func (p *Parser) processCheckBigInt(obj oj.OJsonObject, format bigIntParseFormat
 		return mj.JSONCheckBigInt{}, err
 	}
 	return mj.JSONCheckBigInt{
-		Value:    jbi.Value,
-		IsStar:   false,
-		Original: jbi.Original,
+		Value:            jbi.Value,
+		IsStar:           false,
+		Original:         jbi.Original,
+		OriginalIsNumber: jbi.OriginalIsNumber,
 	}, nil
 }
 
 func (p *Parser) processBigInt(obj oj.OJsonObject, format bigIntParseFormat) (mj.JSONBigInt, error) {
-	strVal, err := p.parseString(obj)
+	strVal, err := p.parseNumericString(obj)
 	if err != nil {
 		return mj.JSONBigInt{}, err
 	}
 
 	bi, err := p.parseBigInt(strVal, format)
+	if err != nil {
+		err = errorAt(obj.Position(), err)
+	}
+	original, isNumber := originalText(obj, strVal)
 	return mj.JSONBigInt{
-		Value:    bi,
-		Original: strVal,
+		Value:            bi,
+		Original:         original,
+		OriginalIsNumber: isNumber,
 	}, err
 }
 
@@ -78,9 +84,10 @@ func (p *Parser) processCheckUint64(obj oj.OJsonObject) (mj.JSONCheckUint64, err
 		return mj.JSONCheckUint64{}, err
 	}
 	return mj.JSONCheckUint64{
-		Value:    ju.Value,
-		IsStar:   false,
-		Original: ju.Original}, nil
+		Value:            ju.Value,
+		IsStar:           false,
+		Original:         ju.Original,
+		OriginalIsNumber: ju.OriginalIsNumber}, nil
 
 }
 
@@ -91,12 +98,13 @@ func (p *Parser) processUint64(obj oj.OJsonObject) (mj.JSONUint64, error) {
 	}
 
 	if bi.Value == nil || !bi.Value.IsUint64() {
-		return mj.JSONUint64{}, errors.New("value is not uint64")
+		return mj.JSONUint64{}, errorAtf(obj.Position(), "value is not uint64")
 	}
 
 	return mj.JSONUint64{
-		Value:    bi.Value.Uint64(),
-		Original: bi.Original}, nil
+		Value:            bi.Value.Uint64(),
+		Original:         bi.Original,
+		OriginalIsNumber: bi.OriginalIsNumber}, nil
 }
 
 func (p *Parser) parseCheckBytes(obj oj.OJsonObject) (mj.JSONCheckBytes, error) {
@@ -117,28 +125,62 @@ func (p *Parser) parseCheckBytes(obj oj.OJsonObject) (mj.JSONCheckBytes, error)
 }
 
 func (p *Parser) processStringAsByteArray(obj oj.OJsonObject) (mj.JSONBytesFromString, error) {
-	strVal, err := p.parseString(obj)
+	strVal, err := p.parseNumericString(obj)
 	if err != nil {
 		return mj.JSONBytesFromString{}, err
 	}
 	result, err := p.ValueInterpreter.InterpretString(strVal)
-	return mj.NewJSONBytesFromString(result, strVal), err
+	if err != nil {
+		err = errorAt(obj.Position(), err)
+	}
+	bytesFromString := mj.NewJSONBytesFromString(result, strVal)
+	bytesFromString.Original, bytesFromString.OriginalIsNumber = originalText(obj, strVal)
+	return bytesFromString, err
 }
 
 func (p *Parser) processSubTreeAsByteArray(obj oj.OJsonObject) (mj.JSONBytesFromTree, error) {
 	value, err := p.ValueInterpreter.InterpretSubTree(obj)
+	if err != nil {
+		err = errorAt(obj.Position(), err)
+	}
 	return mj.JSONBytesFromTree{
 		Value:    value,
 		Original: obj,
 	}, err
 }
 
+// parseString also accepts plain JSON numbers, in which case it yields the number literal.
 func (p *Parser) parseString(obj oj.OJsonObject) (string, error) {
-	str, isStr := obj.(*oj.OJsonString)
-	if !isStr {
-		return "", errors.New("not a string value")
+	switch j := obj.(type) {
+	case *oj.OJsonString:
+		return j.Value, nil
+	case *oj.OJsonNumber:
+		return j.Value, nil
+	default:
+		return "", errorAtf(obj.Position(), "not a string value")
+	}
+}
+
+// parseNumericString accepts both strings and plain JSON numbers.
+// JSON numbers are converted to decimal integer strings, so that "1e3" becomes "1000".
+// This is only for interpreting the value, originalText keeps the number as written.
+func (p *Parser) parseNumericString(obj oj.OJsonObject) (string, error) {
+	if num, isNum := obj.(*oj.OJsonNumber); isNum {
+		bi, isWhole := num.BigInt()
+		if !isWhole {
+			return "", errorAtf(obj.Position(), "number is not an integer: %s", num.Value)
+		}
+		return bi.String(), nil
+	}
+	return p.parseString(obj)
+}
+
+// originalText yields the value as written: the JSON number literal, if it was one, otherwise the string.
+func originalText(obj oj.OJsonObject, strVal string) (string, bool) {
+	if num, isNum := obj.(*oj.OJsonNumber); isNum {
+		return num.Value, true
 	}
-	return str.Value, nil
+	return strVal, false
 }
 
 // IsStar returns whether check object is othe form "*".
@@ -4,6 +4,8 @@ import (
 	"math/big"
 	"testing"
 
+	vi "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/valueinterpreter"
+	oj "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson"
 	"github.com/stretchr/testify/require"
 )
 
@@ -37,3 +39,68 @@ func TestBigInt(t *testing.T) {
 	require.Nil(t, err)
 	require.True(t, big.NewInt(0).Cmp(result) == 0)
 }
+
+func TestJSONNumbers(t *testing.T) {
+	p := Parser{}
+	jobj, err := oj.ParseOrderedJSON([]byte(`[5, 1e18, 123456789012345678901234567890, -1, 2.5, null]`))
+	require.Nil(t, err)
+	items := jobj.(*oj.OJsonList).AsList()
+
+	ju, err := p.processUint64(items[0])
+	require.Nil(t, err)
+	require.Equal(t, uint64(5), ju.Value)
+	require.Equal(t, "5", ju.Original)
+
+	ju, err = p.processUint64(items[1])
+	require.Nil(t, err)
+	require.Equal(t, uint64(1000000000000000000), ju.Value)
+	require.Equal(t, "1e18", ju.Original)
+	require.True(t, ju.OriginalIsNumber)
+
+	jbi, err := p.processBigInt(items[2], bigIntUnsignedBytes)
+	require.Nil(t, err)
+	expected, _ := big.NewInt(0).SetString("123456789012345678901234567890", 10)
+	require.Equal(t, expected, jbi.Value)
+
+	jbi, err = p.processBigInt(items[3], bigIntSignedBytes)
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(-1), jbi.Value)
+
+	_, err = p.processBigInt(items[4], bigIntSignedBytes)
+	require.NotNil(t, err)
+
+	str, err := p.parseString(items[2])
+	require.Nil(t, err)
+	require.Equal(t, "123456789012345678901234567890", str)
+
+	_, err = p.parseString(items[5])
+	require.NotNil(t, err)
+}
+
+func TestParserWithRegistry(t *testing.T) {
+	registry := vi.NewDefaultRegistry()
+	require.Nil(t, registry.RegisterTextPrefix("twice:", func(interpreter *vi.ValueInterpreter, arg []byte) ([]byte, error) {
+		return append(append([]byte{}, arg...), arg...), nil
+	}))
+	p := NewParserWithRegistry(nil, registry)
+	result, err := p.processStringAsByteArray(&oj.OJsonString{Value: "twice:ab|u8:1"})
+	require.Nil(t, err)
+	require.Equal(t, []byte("abab\x01"), result.Value)
+
+	p = NewParser(nil)
+	_, err = p.processStringAsByteArray(&oj.OJsonString{Value: "twice:ab"})
+	require.NotNil(t, err)
+}
+
+func TestBech32AccountAddress(t *testing.T) {
+	p := NewParser(nil)
+	address, err := p.parseAccountAddress("erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th", oj.Position{})
+	require.Nil(t, err)
+	require.Equal(t, "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th", address.Original)
+	require.Equal(t, byte(0x01), address.Value[0])
+	require.Equal(t, 32, len(address.Value))
+
+	_, err = p.parseAccountAddress("erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6tt", oj.Position{})
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "invalid bech32 address: invalid bech32 checksum")
+}
@@ -3,18 +3,43 @@ package mandosjsonparse
 import (
 	fr "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/fileresolver"
 	vi "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/valueinterpreter"
+	oj "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson"
 )
 
 // Parser performs parsing of both json tests (older) and scenarios (new).
 type Parser struct {
 	ValueInterpreter vi.ValueInterpreter
+
+	// JSONOptions configures the underlying JSON parser, e.g. to accept comments.
+	// By default, duplicate keys are errors, set JSONOptions.DuplicateKeys to migrate older files.
+	JSONOptions oj.ParseOptions
+}
+
+// DefaultJSONOptions yields the JSON parser options used by NewParser.
+// The limits are generous for hand-written scenarios, but stop malicious files
+// from exhausting the stack or the memory of a shared CI.
+func DefaultJSONOptions() oj.ParseOptions {
+	return oj.ParseOptions{
+		MaxDepth:        64,
+		MaxDocumentSize: 64 << 20,
+		MaxStringLength: 16 << 20,
+		MaxKeyCount:     100000,
+	}
 }
 
 // NewParser provides a new Parser instance.
 func NewParser(fileResolver fr.FileResolver) Parser {
+	return NewParserWithRegistry(fileResolver, vi.NewDefaultRegistry())
+}
+
+// NewParserWithRegistry provides a new Parser instance, that interprets values with the prefixes in the registry.
+// Start from vi.NewDefaultRegistry() to keep the built-in prefixes.
+func NewParserWithRegistry(fileResolver fr.FileResolver, registry *vi.Registry) Parser {
 	return Parser{
 		ValueInterpreter: vi.ValueInterpreter{
 			FileResolver: fileResolver,
+			Registry:     registry,
 		},
+		JSONOptions: DefaultJSONOptions(),
 	}
 }
@@ -0,0 +1,147 @@
+package mandosvalueinterpreter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AddressHRP is the human readable part of Elrond bech32 addresses, e.g. "erd1...".
+const AddressHRP = "erd"
+
+// addressLength is the length of all account addresses.
+const addressLength = 32
+
+// DecodeBech32Address decodes an address, e.g. "erd1...".
+// The checksum, the human readable part and the length of the address are checked.
+func DecodeBech32Address(str string) ([]byte, error) {
+	hrp, address, err := Bech32Decode(str)
+	if err != nil {
+		return nil, err
+	}
+	if hrp != AddressHRP {
+		return nil, fmt.Errorf("bech32 address should start with %s1", AddressHRP)
+	}
+	if len(address) != addressLength {
+		return nil, fmt.Errorf("bech32 address should be %d bytes long, not %d", addressLength, len(address))
+	}
+	return address, nil
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var bech32Generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+// Bech32Decode splits a bech32 string, as specified in BIP-173, into its human readable part and its data.
+// The checksum is verified.
+func Bech32Decode(str string) (string, []byte, error) {
+	if len(str) > 90 {
+		return "", nil, errors.New("bech32 string too long")
+	}
+	if strings.ToLower(str) != str && strings.ToUpper(str) != str {
+		return "", nil, errors.New("bech32 string has mixed case")
+	}
+	str = strings.ToLower(str)
+	separator := strings.LastIndexByte(str, '1')
+	if separator < 1 || separator+7 > len(str) {
+		return "", nil, errors.New("invalid bech32 separator position")
+	}
+	hrp := str[:separator]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, fmt.Errorf("invalid bech32 character: %q", hrp[i])
+		}
+	}
+	values := make([]byte, 0, len(str)-separator-1)
+	for i := separator + 1; i < len(str); i++ {
+		value := strings.IndexByte(bech32Charset, str[i])
+		if value < 0 {
+			return "", nil, fmt.Errorf("invalid bech32 character: %q", str[i])
+		}
+		values = append(values, byte(value))
+	}
+	if bech32Polymod(append(bech32ExpandHRP(hrp), values...)) != 1 {
+		return "", nil, errors.New("invalid bech32 checksum")
+	}
+	data, err := convertBits(values[:len(values)-6], 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return hrp, data, nil
+}
+
+// Bech32Encode yields the bech32 string of the data, with the human readable part in front, e.g. AddressHRP.
+func Bech32Encode(hrp string, data []byte) (string, error) {
+	values, err := convertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	checksumInput := append(bech32ExpandHRP(hrp), values...)
+	checksumInput = append(checksumInput, 0, 0, 0, 0, 0, 0)
+	polymod := bech32Polymod(checksumInput) ^ 1
+	for i := 0; i < 6; i++ {
+		values = append(values, byte(polymod>>uint(5*(5-i)))&31)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, value := range values {
+		sb.WriteByte(bech32Charset[value])
+	}
+	result := sb.String()
+	if len(result) > 90 {
+		return "", errors.New("bech32 string too long")
+	}
+	return result, nil
+}
+
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, value := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(value)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= bech32Generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32ExpandHRP(hrp string) []byte {
+	result := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		result = append(result, hrp[i]>>5)
+	}
+	result = append(result, 0)
+	for i := 0; i < len(hrp); i++ {
+		result = append(result, hrp[i]&31)
+	}
+	return result
+}
+
+// convertBits regroups the bits of the data, e.g. from bytes to the 5 bit values of bech32.
+func convertBits(data []byte, fromBits uint, toBits uint, pad bool) ([]byte, error) {
+	var result []byte
+	acc := uint32(0)
+	bits := uint(0)
+	maxValue := uint32(1)<<toBits - 1
+	for _, value := range data {
+		acc = acc<<fromBits | uint32(value)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte(acc>>bits&maxValue))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			result = append(result, byte(acc<<(toBits-bits)&maxValue))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxValue != 0 {
+		return nil, errors.New("invalid bech32 padding")
+	}
+	return result, nil
+}
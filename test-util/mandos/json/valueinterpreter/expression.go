@@ -0,0 +1,65 @@
+package mandosvalueinterpreter
+
+import "fmt"
+
+// Expression is a parsed mandos value string.
+type Expression interface {
+	// Offset is the position of the expression in the value string, in bytes, from 0.
+	Offset() int
+}
+
+// LiteralExpression is a value without prefix: a number, true or false. It is empty for empty parts, e.g. in "|".
+type LiteralExpression struct {
+	Text string
+	Pos  int
+}
+
+// PrefixExpression applies a prefix, e.g. "u32:" or "keccak256:", to its argument.
+// The argument of prefixes that take text, e.g. "str:" or "address:", is a LiteralExpression holding the text as written.
+type PrefixExpression struct {
+	Prefix   string
+	Argument Expression
+	Pos      int
+}
+
+// ConcatExpression concatenates the values of its parts, e.g. "u32:1|u32:2".
+type ConcatExpression struct {
+	Parts []Expression
+	Pos   int
+}
+
+var _ Expression = (*LiteralExpression)(nil)
+var _ Expression = (*PrefixExpression)(nil)
+var _ Expression = (*ConcatExpression)(nil)
+
+// Offset yields the position of the literal.
+func (e *LiteralExpression) Offset() int {
+	return e.Pos
+}
+
+// Offset yields the position of the prefix.
+func (e *PrefixExpression) Offset() int {
+	return e.Pos
+}
+
+// Offset yields the position of the first part.
+func (e *ConcatExpression) Offset() int {
+	return e.Pos
+}
+
+// ValueError is an error in a value string, with the position of the problem.
+type ValueError struct {
+	Value  string
+	Offset int
+	Err    error
+}
+
+// Error yields the message, with the column of the problem, counted from 1.
+func (e *ValueError) Error() string {
+	return fmt.Sprintf("column %d of %q: %s", e.Offset+1, e.Value, e.Err.Error())
+}
+
+// Unwrap yields the underlying error.
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,249 @@
+package mandosvalueinterpreter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefixKind says how a prefix takes its argument.
+type prefixKind int
+
+const (
+	// prefixText takes the text of one part, verbatim, e.g. "str:", "address:" or "u32:".
+	prefixText prefixKind = iota
+
+	// prefixPath is like prefixText, but at the start of an expression it takes the rest of it, "|" included, e.g. "file:".
+	prefixPath
+
+	// prefixFunction takes an expression: the rest of it, when at its start, otherwise one part, e.g. "keccak256:".
+	prefixFunction
+)
+
+// ParseExpression parses a mandos value string, with the built-in prefixes. The grammar is:
+//
+//	expression = function-prefix expression | path-prefix rest | concat
+//	concat     = part { "|" part }
+//	part       = "(" expression ")" | function-prefix part | text-prefix text | text
+//
+// So "|" binds looser than the prefixes, except for prefixes like "keccak256:" at the start of an expression,
+// which apply to all of it: "keccak256:str:a|str:b" hashes "ab", while "str:a|keccak256:str:b|str:c" only hashes "b".
+// Parentheses group, but only at the start of a part, elsewhere they are text, e.g. "str:f(x)".
+// In text, a backslash escapes "|", "(" and ")", e.g. "str:a\|b" is the string "a|b".
+// Other backslashes are kept as they are, e.g. "str:a\\b" has two of them.
+// Inside a group, ")" ends the text, so it needs to be escaped too.
+// Parts can be empty, e.g. "|0x01", which is the same as "0x01".
+func ParseExpression(value string) (Expression, error) {
+	return defaultRegistry.ParseExpression(value)
+}
+
+// ParseExpression parses a mandos value string, like the package function, with the prefixes in the registry.
+func (r *Registry) ParseExpression(value string) (Expression, error) {
+	p := &expressionParser{
+		tokenizer: newValueTokenizer(value, r),
+	}
+	expr, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.tokenizer.peekSeparator(); tok.kind != tokenEnd {
+		return nil, p.errorAt(tok.pos, fmt.Errorf("unexpected %q", value[tok.pos]))
+	}
+	return expr, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenPipe
+	tokenOpen
+	tokenClose
+	tokenOther
+)
+
+type valueToken struct {
+	kind tokenKind
+	pos  int
+}
+
+// valueTokenizer splits a value string. What a character means depends on where it is,
+// e.g. "(" only opens a group at the start of a part, so the parser says what it expects next.
+type valueTokenizer struct {
+	input    string
+	pos      int
+	depth    int // open parentheses
+	registry *Registry
+}
+
+func newValueTokenizer(input string, registry *Registry) *valueTokenizer {
+	return &valueTokenizer{
+		input:    input,
+		registry: registry,
+	}
+}
+
+// prefix consumes a prefix, if the input continues with one.
+func (t *valueTokenizer) prefix() (string, prefixKind, bool) {
+	rest := t.input[t.pos:]
+	for _, prefix := range t.registry.matchOrder {
+		if strings.HasPrefix(rest, prefix) {
+			t.pos += len(prefix)
+			return prefix, t.registry.prefixes[prefix].kind, true
+		}
+	}
+	return "", prefixText, false
+}
+
+// open consumes the "(" that starts a group, if there is one.
+func (t *valueTokenizer) open() (valueToken, bool) {
+	if t.pos < len(t.input) && t.input[t.pos] == '(' {
+		t.depth++
+		t.pos++
+		return valueToken{kind: tokenOpen, pos: t.pos - 1}, true
+	}
+	return valueToken{}, false
+}
+
+// peekSeparator yields what follows a part: "|", the ")" closing a group, or the end.
+func (t *valueTokenizer) peekSeparator() valueToken {
+	if t.pos >= len(t.input) {
+		return valueToken{kind: tokenEnd, pos: t.pos}
+	}
+	switch {
+	case t.input[t.pos] == '|':
+		return valueToken{kind: tokenPipe, pos: t.pos}
+	case t.input[t.pos] == ')' && t.depth > 0:
+		return valueToken{kind: tokenClose, pos: t.pos}
+	default:
+		return valueToken{kind: tokenOther, pos: t.pos}
+	}
+}
+
+// consume moves past a separator.
+func (t *valueTokenizer) consume(tok valueToken) {
+	if tok.kind == tokenClose {
+		t.depth--
+	}
+	t.pos = tok.pos + 1
+}
+
+// isTextEnd says whether the character ends the text of a part.
+func (t *valueTokenizer) isTextEnd(c byte, untilClose bool) bool {
+	return (c == '|' && !untilClose) || (c == ')' && t.depth > 0)
+}
+
+// text consumes the text of a part, up to "|", the ")" closing a group, or the end, and unescapes it.
+// With untilClose, "|" is text too, and parentheses only need to be balanced.
+func (t *valueTokenizer) text(untilClose bool) string {
+	var sb strings.Builder
+	nested := 0
+	for t.pos < len(t.input) {
+		c := t.input[t.pos]
+		if c == '\\' && t.pos+1 < len(t.input) && strings.IndexByte("|()", t.input[t.pos+1]) >= 0 {
+			sb.WriteByte(t.input[t.pos+1])
+			t.pos += 2
+			continue
+		}
+		if untilClose && c == '(' {
+			nested++
+		}
+		if nested == 0 && t.isTextEnd(c, untilClose) {
+			break
+		}
+		if untilClose && c == ')' && nested > 0 {
+			nested--
+		}
+		sb.WriteByte(c)
+		t.pos++
+	}
+	return sb.String()
+}
+
+type expressionParser struct {
+	tokenizer *valueTokenizer
+}
+
+func (p *expressionParser) errorAt(pos int, err error) error {
+	return &ValueError{Value: p.tokenizer.input, Offset: pos, Err: err}
+}
+
+func (p *expressionParser) parseExpression() (Expression, error) {
+	start := p.tokenizer.pos
+	prefix, kind, isPrefix := p.tokenizer.prefix()
+	switch {
+	case isPrefix && kind == prefixFunction:
+		argument, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		return &PrefixExpression{Prefix: prefix, Argument: argument, Pos: start}, nil
+	case isPrefix && kind == prefixPath:
+		textPos := p.tokenizer.pos
+		text := p.tokenizer.text(true)
+		return &PrefixExpression{
+			Prefix:   prefix,
+			Argument: &LiteralExpression{Text: text, Pos: textPos},
+			Pos:      start,
+		}, nil
+	}
+	p.tokenizer.pos = start
+	return p.parseConcat()
+}
+
+func (p *expressionParser) parseConcat() (Expression, error) {
+	start := p.tokenizer.pos
+	var parts []Expression
+	for {
+		part, err := p.parsePart()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+
+		tok := p.tokenizer.peekSeparator()
+		if tok.kind != tokenPipe {
+			// the end, or the ")" closing a group, which is up to the caller
+			break
+		}
+		p.tokenizer.consume(tok)
+	}
+	if len(parts) == 1 {
+		return parts[0], nil
+	}
+	return &ConcatExpression{Parts: parts, Pos: start}, nil
+}
+
+func (p *expressionParser) parsePart() (Expression, error) {
+	start := p.tokenizer.pos
+	if open, isOpen := p.tokenizer.open(); isOpen {
+		expr, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		tok := p.tokenizer.peekSeparator()
+		if tok.kind != tokenClose {
+			return nil, p.errorAt(open.pos, errors.New("missing ')'"))
+		}
+		p.tokenizer.consume(tok)
+		if next := p.tokenizer.peekSeparator(); next.kind == tokenOther {
+			return nil, p.errorAt(next.pos, errors.New("'|' expected after ')'"))
+		}
+		return expr, nil
+	}
+
+	prefix, kind, isPrefix := p.tokenizer.prefix()
+	if isPrefix && kind == prefixFunction {
+		argument, err := p.parsePart()
+		if err != nil {
+			return nil, err
+		}
+		return &PrefixExpression{Prefix: prefix, Argument: argument, Pos: start}, nil
+	}
+	textPos := p.tokenizer.pos
+	literal := &LiteralExpression{Text: p.tokenizer.text(false), Pos: textPos}
+	if isPrefix {
+		return &PrefixExpression{Prefix: prefix, Argument: literal, Pos: start}, nil
+	}
+	return literal, nil
+}
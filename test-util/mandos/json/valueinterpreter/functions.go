@@ -1,19 +1,22 @@
 package mandosvalueinterpreter
 
 import (
-	"golang.org/x/crypto/sha3"
+	"bytes"
+	"encoding/hex"
+	"fmt"
 )
 
-// Keccak256 cryptographic function
-// TODO: externalize the same way as the file resolver
-func keccak256(data []byte) ([]byte, error) {
-	hash := sha3.NewLegacyKeccak256()
-	hash.Write(data)
-	result := hash.Sum(nil)
-	return result, nil
-}
+// scAddressZeroBytes is the number of zero bytes in front of smart contract addresses.
+const scAddressZeroBytes = 8
+
+// scAddressVMType marks smart contract addresses as WASM contracts, after the zero bytes.
+var scAddressVMType = []byte{0x05, 0x00}
+
+// scAddressShardSeparator separates the name from the shard bytes in "sc:name#hex".
+const scAddressShardSeparator = "#"
 
 // Generates a 32-byte address based on the input.
+// The name should not start with a zero byte, then it never collides with the addresses of "sc:".
 func address(data []byte) ([]byte, error) {
 	if len(data) > 32 {
 		return data[:32], nil
@@ -28,3 +31,33 @@ func address(data []byte) ([]byte, error) {
 	}
 	return result[:], nil
 }
+
+// Generates a 32-byte smart contract address based on the input, laid out like the protocol does:
+// 8 zero bytes, the VM type, then the name padded with "_", like for "address:".
+// The last 1 or 2 bytes, which decide the shard, can be given in hex after a "#", e.g. "sc:name#01".
+// Addresses of "sc:" start with zero bytes, so they do not collide with those of "address:".
+func scAddress(data []byte) ([]byte, error) {
+	var shardBytes []byte
+	if separator := bytes.LastIndex(data, []byte(scAddressShardSeparator)); separator >= 0 {
+		shardHex := string(data[separator+len(scAddressShardSeparator):])
+		decoded, err := hex.DecodeString(shardHex)
+		if err != nil || len(decoded) == 0 || len(decoded) > 2 {
+			return []byte{}, fmt.Errorf("invalid smart contract address shard bytes, 1 or 2 bytes in hex expected: %s", shardHex)
+		}
+		shardBytes = decoded
+		data = data[:separator]
+	}
+
+	result := make([]byte, 0, 32)
+	result = append(result, make([]byte, scAddressZeroBytes)...)
+	result = append(result, scAddressVMType...)
+	nameLength := 32 - len(result) - len(shardBytes)
+	if len(data) > nameLength {
+		data = data[:nameLength]
+	}
+	result = append(result, data...)
+	for len(result) < 32-len(shardBytes) {
+		result = append(result, byte('_'))
+	}
+	return append(result, shardBytes...), nil
+}
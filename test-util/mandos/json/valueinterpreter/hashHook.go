@@ -0,0 +1,23 @@
+package mandosvalueinterpreter
+
+import (
+	mockhookcrypto "github.com/ElrondNetwork/elrond-vm-util/mock-hook-crypto"
+)
+
+// HashHook computes the hashes of the "keccak256:", "sha256:" and "ripemd160:" prefixes.
+// It is the hashing part of the VM crypto hook, so that scenarios compute hashes the same way the VM does.
+type HashHook interface {
+	// Keccak256 cryptographic function
+	Keccak256(data []byte) ([]byte, error)
+
+	// Sha256 cryptographic function
+	Sha256(data []byte) ([]byte, error)
+
+	// Ripemd160 cryptographic function
+	Ripemd160(data []byte) ([]byte, error)
+}
+
+var _ HashHook = mockhookcrypto.KryptoHookMockInstance
+
+// DefaultHashHook is used by interpreters without a hash hook.
+var DefaultHashHook HashHook = mockhookcrypto.KryptoHookMockInstance
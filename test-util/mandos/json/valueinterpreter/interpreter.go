@@ -15,8 +15,12 @@ import (
 var strPrefixes = []string{"str:", "``", "''"}
 
 const addrPrefix = "address:"
+const scAddrPrefix = "sc:"
+const bech32Prefix = "bech32:"
 const filePrefix = "file:"
 const keccak256Prefix = "keccak256:"
+const sha256Prefix = "sha256:"
+const ripemd160Prefix = "ripemd160:"
 
 const u64Prefix = "u64:"
 const u32Prefix = "u32:"
@@ -30,10 +34,30 @@ const i8Prefix = "i8:"
 // ValueInterpreter provides context for computing Mandos values.
 type ValueInterpreter struct {
 	FileResolver fr.FileResolver
+
+	// HashHook computes hashes, DefaultHashHook if nil.
+	HashHook HashHook
+
+	// Registry holds the prefixes, the built-in ones if nil.
+	Registry *Registry
+}
+
+func (vi *ValueInterpreter) registry() *Registry {
+	if vi.Registry == nil {
+		return defaultRegistry
+	}
+	return vi.Registry
+}
+
+func (vi *ValueInterpreter) hashHook() HashHook {
+	if vi.HashHook == nil {
+		return DefaultHashHook
+	}
+	return vi.HashHook
 }
 
 // InterpretSubTree attempts to produce a value based on a JSON subtree.
-// Subtrees are composed of strings, lists and maps.
+// Subtrees are composed of strings, numbers, lists and maps.
 // The idea is to intuitively represent serialized objects.
 // Lists are evaluated by concatenating their items' representations.
 // Maps are evaluated by concatenating their values' representations (keys are ignored).
@@ -43,6 +67,14 @@ func (vi *ValueInterpreter) InterpretSubTree(obj oj.OJsonObject) ([]byte, error)
 		return vi.InterpretString(str.Value)
 	}
 
+	if num, isNum := obj.(*oj.OJsonNumber); isNum {
+		bi, isWhole := num.BigInt()
+		if !isWhole {
+			return []byte{}, fmt.Errorf("number is not an integer: %s", num.Value)
+		}
+		return vi.InterpretString(bi.String())
+	}
+
 	if list, isList := obj.(*oj.OJsonList); isList {
 		var concat []byte
 		for _, item := range list.AsList() {
@@ -78,55 +110,63 @@ func (vi *ValueInterpreter) InterpretSubTree(obj oj.OJsonObject) ([]byte, error)
 // - ascii strings as "str:...", "``...", "''..."
 // - "true"/"false"
 // - "address:..."
+// - "sc:...", smart contract addresses: 8 zero bytes, the VM type, then the name, e.g. "sc:name#01" ends in 0x01
+// - "bech32:erd1...", 32-byte addresses, which are checked
 // - "file:..."
-// - "keccak256:..."
+// - "keccak256:...", "sha256:...", "ripemd160:..."
 // - concatenation using |
+// - grouping using parentheses
+// - other prefixes, from the registry
 //
+// See ParseExpression for the precise syntax. Errors give the column of the problem in the string.
 func (vi *ValueInterpreter) InterpretString(strRaw string) ([]byte, error) {
 	if len(strRaw) == 0 {
 		return []byte{}, nil
 	}
-
-	// file contents
-	// TODO: make this part of a proper parser
-	if strings.HasPrefix(strRaw, filePrefix) {
-		if vi.FileResolver == nil {
-			return []byte{}, errors.New("parser FileResolver not provided")
-		}
-		fileContents, err := vi.FileResolver.ResolveFileValue(strRaw[len(filePrefix):])
-		if err != nil {
-			return []byte{}, err
-		}
-		return fileContents, nil
-	}
-
-	// keccak256
-	// TODO: make this part of a proper parser
-	if strings.HasPrefix(strRaw, keccak256Prefix) {
-		arg, err := vi.InterpretString(strRaw[len(keccak256Prefix):])
-		if err != nil {
-			return []byte{}, fmt.Errorf("cannot parse keccak256 argument: %w", err)
-		}
-		hash, err := keccak256(arg)
-		if err != nil {
-			return []byte{}, fmt.Errorf("error computing keccak256: %w", err)
-		}
-		return hash, nil
+	expr, err := vi.registry().ParseExpression(strRaw)
+	if err != nil {
+		return []byte{}, err
 	}
+	return vi.InterpretExpression(strRaw, expr)
+}
 
-	// concatenate values of different formats
-	// TODO: make this part of a proper parser
-	parts := strings.Split(strRaw, "|")
-	if len(parts) > 1 {
+// InterpretExpression computes the value of a parsed expression.
+// The value string is only used in error messages.
+func (vi *ValueInterpreter) InterpretExpression(value string, expr Expression) ([]byte, error) {
+	switch e := expr.(type) {
+	case *ConcatExpression:
 		concat := make([]byte, 0)
-		for _, part := range parts {
-			eval, err := vi.InterpretString(part)
+		for _, part := range e.Parts {
+			eval, err := vi.InterpretExpression(value, part)
 			if err != nil {
 				return []byte{}, err
 			}
 			concat = append(concat, eval...)
 		}
 		return concat, nil
+	case *PrefixExpression:
+		result, err := vi.interpretPrefix(value, e)
+		if err != nil {
+			if _, isValueError := err.(*ValueError); !isValueError {
+				err = &ValueError{Value: value, Offset: e.Argument.Offset(), Err: err}
+			}
+			return []byte{}, err
+		}
+		return result, nil
+	case *LiteralExpression:
+		result, err := vi.interpretLiteral(e.Text)
+		if err != nil {
+			return []byte{}, &ValueError{Value: value, Offset: e.Pos, Err: err}
+		}
+		return result, nil
+	default:
+		return []byte{}, errors.New("unknown expression type")
+	}
+}
+
+func (vi *ValueInterpreter) interpretLiteral(strRaw string) ([]byte, error) {
+	if len(strRaw) == 0 {
+		return []byte{}, nil
 	}
 
 	if strRaw == "false" {
@@ -137,31 +177,29 @@ func (vi *ValueInterpreter) InterpretString(strRaw string) ([]byte, error) {
 		return []byte{0x01}, nil
 	}
 
-	// allow ascii strings, for readability
-	for _, strPrefix := range strPrefixes {
-		if strings.HasPrefix(strRaw, strPrefix) {
-			str := strRaw[len(strPrefix):]
-			return []byte(str), nil
-		}
-	}
+	// general numbers, arbitrary length
+	return vi.interpretNumber(strRaw, 0)
+}
 
-	// address
-	if strings.HasPrefix(strRaw, addrPrefix) {
-		addrName := strRaw[len(addrPrefix):]
-		return address([]byte(addrName))
+func (vi *ValueInterpreter) interpretPrefix(value string, e *PrefixExpression) ([]byte, error) {
+	registered, isRegistered := vi.registry().prefixes[e.Prefix]
+	if !isRegistered {
+		return []byte{}, fmt.Errorf("unknown prefix: %s", e.Prefix)
 	}
 
-	// fixed width numbers
-	parsed, result, err := vi.tryInterpretFixedWidth(strRaw)
-	if err != nil {
-		return nil, err
-	}
-	if parsed {
-		return result, nil
+	if registered.kind == prefixFunction {
+		arg, err := vi.InterpretExpression(value, e.Argument)
+		if err != nil {
+			return []byte{}, err
+		}
+		return registered.fn(vi, arg)
 	}
 
-	// general numbers, arbitrary length
-	return vi.interpretNumber(strRaw, 0)
+	literal, isLiteral := e.Argument.(*LiteralExpression)
+	if !isLiteral {
+		return []byte{}, fmt.Errorf("%s needs text, not an expression", e.Prefix)
+	}
+	return registered.fn(vi, []byte(literal.Text))
 }
 
 // targetWidth = 0 means minimum length that can contain the result
@@ -241,41 +279,3 @@ func (vi *ValueInterpreter) interpretUnsignedNumberFixedWidth(strRaw string, tar
 	}
 	return twos.CopyAlignRight(numberBytes, targetWidth), nil
 }
-
-func (vi *ValueInterpreter) tryInterpretFixedWidth(strRaw string) (bool, []byte, error) {
-	if strings.HasPrefix(strRaw, u64Prefix) {
-		r, err := vi.interpretUnsignedNumberFixedWidth(strRaw[len(u64Prefix):], 8)
-		return true, r, err
-	}
-	if strings.HasPrefix(strRaw, u32Prefix) {
-		r, err := vi.interpretUnsignedNumberFixedWidth(strRaw[len(u32Prefix):], 4)
-		return true, r, err
-	}
-	if strings.HasPrefix(strRaw, u16Prefix) {
-		r, err := vi.interpretUnsignedNumberFixedWidth(strRaw[len(u16Prefix):], 2)
-		return true, r, err
-	}
-	if strings.HasPrefix(strRaw, u8Prefix) {
-		r, err := vi.interpretUnsignedNumberFixedWidth(strRaw[len(u8Prefix):], 1)
-		return true, r, err
-	}
-
-	if strings.HasPrefix(strRaw, i64Prefix) {
-		r, err := vi.interpretNumber(strRaw[len(i64Prefix):], 8)
-		return true, r, err
-	}
-	if strings.HasPrefix(strRaw, i32Prefix) {
-		r, err := vi.interpretNumber(strRaw[len(i32Prefix):], 4)
-		return true, r, err
-	}
-	if strings.HasPrefix(strRaw, i16Prefix) {
-		r, err := vi.interpretNumber(strRaw[len(i16Prefix):], 2)
-		return true, r, err
-	}
-	if strings.HasPrefix(strRaw, i8Prefix) {
-		r, err := vi.interpretNumber(strRaw[len(i8Prefix):], 1)
-		return true, r, err
-	}
-
-	return false, []byte{}, nil
-}
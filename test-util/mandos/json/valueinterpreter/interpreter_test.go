@@ -1,9 +1,12 @@
 package mandosvalueinterpreter
 
 import (
+	"bytes"
 	"encoding/hex"
+	"errors"
 	"testing"
 
+	mockhookcrypto "github.com/ElrondNetwork/elrond-vm-util/mock-hook-crypto"
 	fr "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/fileresolver"
 	oj "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson"
 	"github.com/stretchr/testify/require"
@@ -314,6 +317,8 @@ func TestConcat(t *testing.T) {
 	require.Equal(t, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00}, result)
 }
 
+var keccak256 = mockhookcrypto.KryptoHookMockInstance.Keccak256
+
 func TestKeccak256(t *testing.T) {
 	vi := ValueInterpreter{}
 	result, err := vi.InterpretString("keccak256:0x01|5")
@@ -421,3 +426,210 @@ func TestInterpretSubTree2(t *testing.T) {
 	expected = append(expected, []byte("field2elem3b")...)
 	require.Equal(t, expected, result)
 }
+
+func TestGroupingAndEscapes(t *testing.T) {
+	vi := ValueInterpreter{}
+	result, err := vi.InterpretString("''a\\|b|''c")
+	require.Nil(t, err)
+	require.Equal(t, []byte("a|bc"), result)
+
+	result, err = vi.InterpretString("str:f(x)")
+	require.Nil(t, err)
+	require.Equal(t, []byte("f(x)"), result)
+
+	result, err = vi.InterpretString("(''a|''b)|u8:1")
+	require.Nil(t, err)
+	require.Equal(t, []byte("ab\x01"), result)
+
+	// a hash at the start applies to everything, other hashes to one part, or to a group
+	expected, _ := keccak256([]byte("bc"))
+	result, err = vi.InterpretString("''a|keccak256:(''b|''c)|''d")
+	require.Nil(t, err)
+	require.Equal(t, append(append([]byte("a"), expected...), 'd'), result)
+
+	expected, _ = keccak256([]byte("a"))
+	result, err = vi.InterpretString("(keccak256:''a)|''b")
+	require.Nil(t, err)
+	require.Equal(t, append(expected, 'b'), result)
+
+	expected, _ = keccak256([]byte("ab"))
+	result, err = vi.InterpretString("keccak256:(''a)|''b")
+	require.Nil(t, err)
+	require.Equal(t, expected, result)
+
+	result, err = vi.InterpretString("(''a\\)b)")
+	require.Nil(t, err)
+	require.Equal(t, []byte("a)b"), result)
+
+	// only "|", "(" and ")" can be escaped, other backslashes are kept as they are
+	result, err = vi.InterpretString(`str:a\\b|str:c\d`)
+	require.Nil(t, err)
+	require.Equal(t, []byte(`a\\bc\d`), result)
+
+	result, err = vi.InterpretString(`str:a\\\|b`)
+	require.Nil(t, err)
+	require.Equal(t, []byte(`a\\|b`), result)
+}
+
+func TestExpressionAST(t *testing.T) {
+	expr, err := ParseExpression("keccak256:u8:1|(''a)")
+	require.Nil(t, err)
+	require.Equal(t, &PrefixExpression{
+		Prefix: "keccak256:",
+		Argument: &ConcatExpression{
+			Parts: []Expression{
+				&PrefixExpression{Prefix: "u8:", Argument: &LiteralExpression{Text: "1", Pos: 13}, Pos: 10},
+				&PrefixExpression{Prefix: "''", Argument: &LiteralExpression{Text: "a", Pos: 18}, Pos: 16},
+			},
+			Pos: 10,
+		},
+		Pos: 0,
+	}, expr)
+}
+
+func TestValueErrors(t *testing.T) {
+	vi := ValueInterpreter{}
+	_, err := vi.InterpretString("0x01|abc")
+	require.Equal(t, "column 6 of \"0x01|abc\": could not parse base 10 value: abc", err.Error())
+
+	_, err = vi.InterpretString("u8:1|u8:0x1ff")
+	require.Equal(t, "column 9 of \"u8:1|u8:0x1ff\": representation of 0x1ff does not fit in 1 bytes", err.Error())
+
+	_, err = vi.InterpretString("''a|(''b|''c")
+	require.Equal(t, "column 5 of \"''a|(''b|''c\": missing ')'", err.Error())
+
+	_, err = vi.InterpretString("(''b)''c")
+	require.Equal(t, "column 6 of \"(''b)''c\": '|' expected after ')'", err.Error())
+
+	_, err = vi.InterpretString("u32:(5)")
+	require.Equal(t, "column 5 of \"u32:(5)\": could not parse base 10 value: (5)", err.Error())
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewDefaultRegistry()
+	require.Nil(t, registry.RegisterFunction("reverse:", func(vi *ValueInterpreter, arg []byte) ([]byte, error) {
+		result := make([]byte, len(arg))
+		for i, b := range arg {
+			result[len(arg)-1-i] = b
+		}
+		return result, nil
+	}))
+	require.Nil(t, registry.RegisterTextPrefix("upper:", func(vi *ValueInterpreter, arg []byte) ([]byte, error) {
+		return bytes.ToUpper(arg), nil
+	}))
+	require.NotNil(t, registry.RegisterTextPrefix("", nil))
+
+	vi := ValueInterpreter{Registry: registry}
+	result, err := vi.InterpretString("reverse:u16:1|''ab|upper:cd")
+	require.Nil(t, err)
+	require.Equal(t, []byte("DCba\x01\x00"), result)
+
+	result, err = vi.InterpretString("''x|reverse:''ab|upper:cd")
+	require.Nil(t, err)
+	require.Equal(t, []byte("xbaCD"), result)
+
+	// other interpreters are not affected
+	other := ValueInterpreter{}
+	_, err = other.InterpretString("upper:cd")
+	require.NotNil(t, err)
+
+	registry.Unregister("u16:")
+	_, err = vi.InterpretString("u16:1")
+	require.Equal(t, "column 1 of \"u16:1\": could not parse base 10 value: u16:1", err.Error())
+}
+
+func TestSha256Ripemd160(t *testing.T) {
+	vi := ValueInterpreter{}
+	result, err := vi.InterpretString("sha256:str:abc")
+	require.Nil(t, err)
+	require.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", hex.EncodeToString(result))
+
+	result, err = vi.InterpretString("ripemd160:str:abc")
+	require.Nil(t, err)
+	require.Equal(t, "8eb208f7e05d987a9b044a8e98c6b087f15a0bfc", hex.EncodeToString(result))
+
+	result, err = vi.InterpretString("u8:1|sha256:|ripemd160:")
+	require.Nil(t, err)
+	require.Equal(t, 1+32+20, len(result))
+}
+
+type failingHashHook struct {
+	mockhookcrypto.KryptoHookMock
+}
+
+func (failingHashHook) Sha256(data []byte) ([]byte, error) {
+	return nil, errors.New("no sha256 here")
+}
+
+func TestHashHook(t *testing.T) {
+	vi := ValueInterpreter{HashHook: failingHashHook{}}
+	_, err := vi.InterpretString("str:a|sha256:str:b")
+	require.Equal(t, "column 14 of \"str:a|sha256:str:b\": error computing sha256: no sha256 here", err.Error())
+
+	result, err := vi.InterpretString("keccak256:str:b")
+	require.Nil(t, err)
+	expected, _ := keccak256([]byte("b"))
+	require.Equal(t, expected, result)
+}
+
+func TestBech32(t *testing.T) {
+	vi := ValueInterpreter{}
+	result, err := vi.InterpretString("bech32:erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th")
+	require.Nil(t, err)
+	require.Equal(t, "0139472eff6886771a982f3083da5d421f24c29181e63888228dc81ca60d69e1", hex.EncodeToString(result))
+
+	encoded, err := Bech32Encode(AddressHRP, result)
+	require.Nil(t, err)
+	require.Equal(t, "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th", encoded)
+
+	// BIP-173 test vectors
+	hrp, data, err := Bech32Decode("A12UEL5L")
+	require.Nil(t, err)
+	require.Equal(t, "a", hrp)
+	require.Equal(t, 0, len(data))
+	_, _, err = Bech32Decode("abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw")
+	require.Nil(t, err)
+
+	_, err = vi.InterpretString("bech32:erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6tt")
+	require.Equal(t, "column 8 of \"bech32:erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6tt\": invalid bech32 checksum", err.Error())
+	// valid bech32, but not an address
+	_, err = vi.InterpretString("bech32:bc1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqaj76hn")
+	require.Equal(t, "column 8 of \"bech32:bc1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqaj76hn\": bech32 address should start with erd1", err.Error())
+	_, err = vi.InterpretString("bech32:erd1qypqxtyharv")
+	require.Equal(t, "column 8 of \"bech32:erd1qypqxtyharv\": bech32 address should be 32 bytes long, not 3", err.Error())
+
+	_, _, err = Bech32Decode("erd1Qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th")
+	require.Equal(t, "bech32 string has mixed case", err.Error())
+	_, _, err = Bech32Decode("erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6tb")
+	require.NotNil(t, err)
+	_, _, err = Bech32Decode("erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6ti")
+	require.Equal(t, "invalid bech32 character: 'i'", err.Error())
+}
+
+func TestSCAddress(t *testing.T) {
+	vi := ValueInterpreter{}
+	result, err := vi.InterpretString("sc:my_contract")
+	require.Nil(t, err)
+	require.Equal(t, []byte("\x00\x00\x00\x00\x00\x00\x00\x00\x05\x00my_contract___________"), result)
+
+	result, err = vi.InterpretString("sc:my_contract#01")
+	require.Nil(t, err)
+	require.Equal(t, []byte("\x00\x00\x00\x00\x00\x00\x00\x00\x05\x00my_contract__________\x01"), result)
+
+	result, err = vi.InterpretString("sc:a_very_long_contract_name_indeed#0102")
+	require.Nil(t, err)
+	require.Equal(t, []byte("\x00\x00\x00\x00\x00\x00\x00\x00\x05\x00a_very_long_contract\x01\x02"), result)
+
+	// the same name yields different addresses
+	userAddress, err := vi.InterpretString("address:my_contract")
+	require.Nil(t, err)
+	require.Equal(t, 32, len(userAddress))
+	result, err = vi.InterpretString("sc:my_contract")
+	require.Nil(t, err)
+	require.NotEqual(t, userAddress, result)
+
+	_, err = vi.InterpretString("sc:my_contract#010203")
+	require.Equal(t, "column 4 of \"sc:my_contract#010203\": invalid smart contract address shard bytes, 1 or 2 bytes in hex expected: 010203", err.Error())
+	_, err = vi.InterpretString("sc:my_contract#")
+	require.NotNil(t, err)
+}
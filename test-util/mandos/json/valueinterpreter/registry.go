@@ -0,0 +1,163 @@
+package mandosvalueinterpreter
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// PrefixFunc computes the value of a prefix from its argument,
+// which is either an interpreted value, or the text after the prefix, depending on how it was registered.
+type PrefixFunc func(vi *ValueInterpreter, arg []byte) ([]byte, error)
+
+type registeredPrefix struct {
+	kind prefixKind
+	fn   PrefixFunc
+}
+
+// Registry holds the prefixes known to a value interpreter, e.g. "u32:" or "keccak256:".
+// Projects can register their own prefixes, next to the built-in ones.
+// Registering is not safe while values are being interpreted with the same registry.
+type Registry struct {
+	prefixes   map[string]*registeredPrefix
+	matchOrder []string // longest first, so that prefixes of prefixes do not match first
+}
+
+// NewRegistry yields a registry with no prefixes at all, not even the built-in ones.
+func NewRegistry() *Registry {
+	return &Registry{
+		prefixes: make(map[string]*registeredPrefix),
+	}
+}
+
+// NewDefaultRegistry yields a registry with the built-in prefixes.
+// It is a new instance, registering prefixes in it does not affect other interpreters.
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, strPrefix := range strPrefixes {
+		_ = r.RegisterTextPrefix(strPrefix, func(vi *ValueInterpreter, arg []byte) ([]byte, error) {
+			return arg, nil
+		})
+	}
+	_ = r.RegisterTextPrefix(addrPrefix, func(vi *ValueInterpreter, arg []byte) ([]byte, error) {
+		return address(arg)
+	})
+	_ = r.RegisterTextPrefix(scAddrPrefix, func(vi *ValueInterpreter, arg []byte) ([]byte, error) {
+		return scAddress(arg)
+	})
+	_ = r.RegisterTextPrefix(bech32Prefix, func(vi *ValueInterpreter, arg []byte) ([]byte, error) {
+		return DecodeBech32Address(string(arg))
+	})
+	_ = r.register(filePrefix, prefixPath, func(vi *ValueInterpreter, arg []byte) ([]byte, error) {
+		if vi.FileResolver == nil {
+			return []byte{}, errors.New("parser FileResolver not provided")
+		}
+		return vi.FileResolver.ResolveFileValue(string(arg))
+	})
+
+	// hashes
+	r.registerHash(keccak256Prefix, "keccak256", func(hook HashHook, data []byte) ([]byte, error) {
+		return hook.Keccak256(data)
+	})
+	r.registerHash(sha256Prefix, "sha256", func(hook HashHook, data []byte) ([]byte, error) {
+		return hook.Sha256(data)
+	})
+	r.registerHash(ripemd160Prefix, "ripemd160", func(hook HashHook, data []byte) ([]byte, error) {
+		return hook.Ripemd160(data)
+	})
+
+	// fixed width numbers
+	r.registerFixedWidth(u64Prefix, 8, false)
+	r.registerFixedWidth(u32Prefix, 4, false)
+	r.registerFixedWidth(u16Prefix, 2, false)
+	r.registerFixedWidth(u8Prefix, 1, false)
+	r.registerFixedWidth(i64Prefix, 8, true)
+	r.registerFixedWidth(i32Prefix, 4, true)
+	r.registerFixedWidth(i16Prefix, 2, true)
+	r.registerFixedWidth(i8Prefix, 1, true)
+
+	return r
+}
+
+func (r *Registry) registerHash(prefix string, name string, hash func(hook HashHook, data []byte) ([]byte, error)) {
+	_ = r.RegisterFunction(prefix, func(vi *ValueInterpreter, arg []byte) ([]byte, error) {
+		result, err := hash(vi.hashHook(), arg)
+		if err != nil {
+			return []byte{}, fmt.Errorf("error computing %s: %w", name, err)
+		}
+		return result, nil
+	})
+}
+
+func (r *Registry) registerFixedWidth(prefix string, width int, signed bool) {
+	_ = r.RegisterTextPrefix(prefix, func(vi *ValueInterpreter, arg []byte) ([]byte, error) {
+		if len(arg) == 0 {
+			return []byte{}, errors.New("number expected")
+		}
+		if signed {
+			return vi.interpretNumber(string(arg), width)
+		}
+		return vi.interpretUnsignedNumberFixedWidth(string(arg), width)
+	})
+}
+
+// defaultRegistry is used by interpreters without a registry.
+var defaultRegistry = NewDefaultRegistry()
+
+// RegisterFunction registers a prefix whose argument is a value, interpreted before fn gets it, e.g. "keccak256:".
+// At the start of an expression, the prefix applies to all of it, otherwise to one part.
+// Registering a prefix again replaces it.
+func (r *Registry) RegisterFunction(prefix string, fn PrefixFunc) error {
+	return r.register(prefix, prefixFunction, fn)
+}
+
+// RegisterTextPrefix registers a prefix whose argument is the text after it, up to the next "|", e.g. "str:" or "u32:".
+// Registering a prefix again replaces it.
+func (r *Registry) RegisterTextPrefix(prefix string, fn PrefixFunc) error {
+	return r.register(prefix, prefixText, fn)
+}
+
+func (r *Registry) register(prefix string, kind prefixKind, fn PrefixFunc) error {
+	if len(prefix) == 0 {
+		return errors.New("empty prefix")
+	}
+	if fn == nil {
+		return fmt.Errorf("no function given for prefix %s", prefix)
+	}
+	r.prefixes[prefix] = &registeredPrefix{kind: kind, fn: fn}
+	r.updateMatchOrder()
+	return nil
+}
+
+// Unregister removes a prefix. Values starting with it will be read as numbers.
+func (r *Registry) Unregister(prefix string) {
+	delete(r.prefixes, prefix)
+	r.updateMatchOrder()
+}
+
+func (r *Registry) updateMatchOrder() {
+	r.matchOrder = r.Prefixes()
+	sort.SliceStable(r.matchOrder, func(i, j int) bool {
+		return len(r.matchOrder[i]) > len(r.matchOrder[j])
+	})
+}
+
+// Prefixes yields the registered prefixes, sorted.
+func (r *Registry) Prefixes() []string {
+	result := make([]string, 0, len(r.prefixes))
+	for prefix := range r.prefixes {
+		result = append(result, prefix)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Clone yields a copy of the registry, which can be changed independently.
+func (r *Registry) Clone() *Registry {
+	clone := NewRegistry()
+	for prefix, registered := range r.prefixes {
+		clone.prefixes[prefix] = registered
+	}
+	clone.updateMatchOrder()
+	return clone
+}
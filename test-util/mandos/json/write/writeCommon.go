@@ -77,7 +77,7 @@ func blockHashesToOJ(blockHashes []mj.JSONBytesFromString) oj.OJsonObject {
 	for _, blh := range blockHashes {
 		blockhashesList = append(blockhashesList, bytesFromStringToOJ(blh))
 	}
-	blockhashesOJ := oj.OJsonList(blockhashesList)
+	blockhashesOJ := oj.OJsonList{Items: blockhashesList}
 	return &blockhashesOJ
 }
 
@@ -88,7 +88,7 @@ func resultToOJ(res *mj.TransactionResult) oj.OJsonObject {
 	for _, out := range res.Out {
 		outList = append(outList, checkBytesToOJ(out))
 	}
-	outOJ := oj.OJsonList(outList)
+	outOJ := oj.OJsonList{Items: outList}
 	resultOJ.Put("out", &outOJ)
 
 	if !res.Status.IsDefault() {
@@ -131,7 +131,7 @@ func logToOJ(logEntry *mj.LogEntry) oj.OJsonObject {
 	for _, topic := range logEntry.Topics {
 		topicsList = append(topicsList, bytesFromStringToOJ(topic))
 	}
-	topicsOJ := oj.OJsonList(topicsList)
+	topicsOJ := oj.OJsonList{Items: topicsList}
 	logOJ.Put("topics", &topicsOJ)
 
 	logOJ.Put("data", bytesFromStringToOJ(logEntry.Data))
@@ -145,7 +145,7 @@ func logsToOJ(logEntries []*mj.LogEntry) oj.OJsonObject {
 		logOJ := logToOJ(logEntry)
 		logList = append(logList, logOJ)
 	}
-	logOJList := oj.OJsonList(logList)
+	logOJList := oj.OJsonList{Items: logList}
 	return &logOJList
 }
 
@@ -173,11 +173,11 @@ func intToString(i *big.Int) string {
 }
 
 func bigIntToOJ(i mj.JSONBigInt) oj.OJsonObject {
-	return &oj.OJsonString{Value: i.Original}
+	return originalToOJ(i.Original, i.OriginalIsNumber)
 }
 
 func checkBigIntToOJ(i mj.JSONCheckBigInt) oj.OJsonObject {
-	return &oj.OJsonString{Value: i.Original}
+	return originalToOJ(i.Original, i.OriginalIsNumber)
 }
 
 func bytesFromStringToString(bytes mj.JSONBytesFromString) string {
@@ -188,7 +188,15 @@ func bytesFromStringToString(bytes mj.JSONBytesFromString) string {
 }
 
 func bytesFromStringToOJ(bytes mj.JSONBytesFromString) oj.OJsonObject {
-	return &oj.OJsonString{Value: bytesFromStringToString(bytes)}
+	return originalToOJ(bytesFromStringToString(bytes), bytes.OriginalIsNumber)
+}
+
+// originalToOJ writes a value back as it was originally written, as a plain JSON number or as a string.
+func originalToOJ(original string, isNumber bool) oj.OJsonObject {
+	if isNumber {
+		return &oj.OJsonNumber{Value: original}
+	}
+	return &oj.OJsonString{Value: original}
 }
 
 func bytesFromTreeToOJ(bytes mj.JSONBytesFromTree) oj.OJsonObject {
@@ -206,11 +214,11 @@ func checkBytesToOJ(checkBytes mj.JSONCheckBytes) oj.OJsonObject {
 }
 
 func uint64ToOJ(i mj.JSONUint64) oj.OJsonObject {
-	return &oj.OJsonString{Value: i.Original}
+	return originalToOJ(i.Original, i.OriginalIsNumber)
 }
 
 func checkUint64ToOJ(i mj.JSONCheckUint64) oj.OJsonObject {
-	return &oj.OJsonString{Value: i.Original}
+	return originalToOJ(i.Original, i.OriginalIsNumber)
 }
 
 func stringToOJ(str string) oj.OJsonObject {
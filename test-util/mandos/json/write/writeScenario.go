@@ -7,8 +7,19 @@ import (
 
 // ScenarioToJSONString converts a scenario object to its JSON representation.
 func ScenarioToJSONString(scenario *mj.Scenario) string {
+	return ScenarioToJSONStringWithOptions(scenario, oj.FormatOptions{})
+}
+
+// ScenarioToJSONStringWithOptions converts a scenario object to its JSON representation, in the given style.
+func ScenarioToJSONStringWithOptions(scenario *mj.Scenario, options oj.FormatOptions) string {
+	jobj := ScenarioToOrderedJSON(scenario)
+	return oj.JSONStringWithOptions(jobj, options)
+}
+
+// ScenarioToYAMLString converts a scenario object to YAML.
+func ScenarioToYAMLString(scenario *mj.Scenario) string {
 	jobj := ScenarioToOrderedJSON(scenario)
-	return oj.JSONString(jobj)
+	return oj.YAMLString(jobj)
 }
 
 // ScenarioToOrderedJSON converts a scenario object to an ordered JSON object.
@@ -24,7 +35,7 @@ func ScenarioToOrderedJSON(scenario *mj.Scenario) oj.OJsonObject {
 	}
 
 	if !scenario.CheckGas {
-		ojFalse := oj.OJsonBool(false)
+		ojFalse := oj.OJsonBool{Value: false}
 		scenarioOJ.Put("checkGas", &ojFalse)
 	}
 
@@ -80,7 +91,7 @@ func ScenarioToOrderedJSON(scenario *mj.Scenario) oj.OJsonObject {
 		stepOJList = append(stepOJList, stepOJ)
 	}
 
-	stepsOJ := oj.OJsonList(stepOJList)
+	stepsOJ := oj.OJsonList{Items: stepOJList}
 	scenarioOJ.Put("steps", &stepsOJ)
 
 	return scenarioOJ
@@ -107,7 +118,7 @@ func transactionToScenarioOJ(tx *mj.Transaction) oj.OJsonObject {
 		for _, arg := range tx.Arguments {
 			argList = append(argList, bytesFromTreeToOJ(arg))
 		}
-		argOJ := oj.OJsonList(argList)
+		argOJ := oj.OJsonList{Items: argList}
 		transactionOJ.Put("arguments", &argOJ)
 	}
 
@@ -128,7 +139,7 @@ func newAddressMocksToOJ(newAddressMocks []*mj.NewAddressMock) oj.OJsonObject {
 		namOJ.Put("newAddress", bytesFromStringToOJ(namEntry.NewAddress))
 		namList = append(namList, namOJ)
 	}
-	namOJList := oj.OJsonList(namList)
+	namOJList := oj.OJsonList{Items: namList}
 	return &namOJList
 }
 
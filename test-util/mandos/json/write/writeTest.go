@@ -7,8 +7,19 @@ import (
 
 // TestToJSONString converts a test object to its JSON representation.
 func TestToJSONString(testTopLevel []*mj.Test) string {
+	return TestToJSONStringWithOptions(testTopLevel, oj.FormatOptions{})
+}
+
+// TestToJSONStringWithOptions converts a test object to its JSON representation, in the given style.
+func TestToJSONStringWithOptions(testTopLevel []*mj.Test, options oj.FormatOptions) string {
+	jobj := TestToOrderedJSON(testTopLevel)
+	return oj.JSONStringWithOptions(jobj, options)
+}
+
+// TestToYAMLString converts a test object to YAML.
+func TestToYAMLString(testTopLevel []*mj.Test) string {
 	jobj := TestToOrderedJSON(testTopLevel)
-	return oj.JSONString(jobj)
+	return oj.YAMLString(jobj)
 }
 
 // TestToOrderedJSON converts a test object to an ordered JSON object.
@@ -25,7 +36,7 @@ func testToOJ(test *mj.Test) oj.OJsonObject {
 	testOJ := oj.NewMap()
 
 	if !test.CheckGas {
-		ojFalse := oj.OJsonBool(false)
+		ojFalse := oj.OJsonBool{Value: false}
 		testOJ.Put("checkGas", &ojFalse)
 	}
 
@@ -35,7 +46,7 @@ func testToOJ(test *mj.Test) oj.OJsonObject {
 	for _, block := range test.Blocks {
 		blockList = append(blockList, blockToOJ(block))
 	}
-	blocksOJ := oj.OJsonList(blockList)
+	blocksOJ := oj.OJsonList{Items: blockList}
 	testOJ.Put("blocks", &blocksOJ)
 	testOJ.Put("network", stringToOJ(test.Network))
 	testOJ.Put("blockHashes", blockHashesToOJ(test.BlockHashes))
@@ -55,7 +66,7 @@ func transactionToTestOJ(tx *mj.Transaction) oj.OJsonObject {
 	for _, arg := range tx.Arguments {
 		argList = append(argList, bytesFromTreeToOJ(arg))
 	}
-	argOJ := oj.OJsonList(argList)
+	argOJ := oj.OJsonList{Items: argList}
 	transactionOJ.Put("arguments", &argOJ)
 
 	if len(tx.Code.Original) > 0 {
@@ -74,14 +85,14 @@ func blockToOJ(block *mj.Block) oj.OJsonObject {
 	for _, blr := range block.Results {
 		resultList = append(resultList, resultToOJ(blr))
 	}
-	resultsOJ := oj.OJsonList(resultList)
+	resultsOJ := oj.OJsonList{Items: resultList}
 	blockOJ.Put("results", &resultsOJ)
 
 	var txList []oj.OJsonObject
 	for _, tx := range block.Transactions {
 		txList = append(txList, transactionToTestOJ(tx))
 	}
-	txsOJ := oj.OJsonList(txList)
+	txsOJ := oj.OJsonList{Items: txList}
 	blockOJ.Put("transactions", &txsOJ)
 
 	blockHeaderOJ := oj.NewMap()
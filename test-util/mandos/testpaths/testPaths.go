@@ -0,0 +1,42 @@
+package mandostestpaths
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IsExcluded yields true if the test file matches any of the excluded patterns,
+// which are relative to the general test path.
+func IsExcluded(excludedFilePatterns []string, testPath string, generalTestPath string) (bool, error) {
+	for _, et := range excludedFilePatterns {
+		excludedFullPath := path.Join(generalTestPath, et)
+		match, err := filepath.Match(excludedFullPath, testPath)
+		if err != nil {
+			return false, fmt.Errorf("invalid excluded file pattern %s: %w", et, err)
+		}
+		if match {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// HasAnySuffix yields true if the test file ends with any of the suffixes, e.g. ".scen.json".
+func HasAnySuffix(testPath string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(testPath, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ShortenTestPath yields the path of a test file relative to the general test path, for display.
+func ShortenTestPath(testPath string, generalTestPath string) string {
+	if strings.HasPrefix(testPath, generalTestPath+"/") {
+		return testPath[len(generalTestPath)+1:]
+	}
+	return testPath
+}
@@ -0,0 +1,39 @@
+package orderedjson
+
+import (
+	"strings"
+)
+
+// commentsOf yields the comments preceding a node.
+func commentsOf(jobj OJsonObject) []string {
+	switch j := jobj.(type) {
+	case *OJsonMap:
+		return j.Comments
+	case *OJsonList:
+		return j.Comments
+	case *OJsonString:
+		return j.Comments
+	case *OJsonNumber:
+		return j.Comments
+	case *OJsonBool:
+		return j.Comments
+	case *OJsonNull:
+		return j.Comments
+	default:
+		return nil
+	}
+}
+
+// writeComments writes the comments preceding a value.
+// Line comments are followed by a new line, block comments stay on the same line as the value.
+func writeComments(sb *strings.Builder, comments []string, indent string) {
+	for _, comment := range comments {
+		sb.WriteString(comment)
+		if strings.HasPrefix(comment, "//") {
+			sb.WriteString("\n")
+			sb.WriteString(indent)
+		} else {
+			sb.WriteString(" ")
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package orderedjson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const jsoncExample = `// header
+{
+    /* first */ "a": 1,
+    "b": // about b
+        [
+            true,
+            null, // trailing comma
+        ],
+    "c": "not // a comment",
+    // before closing
+}
+`
+
+func TestJSONCComments(t *testing.T) {
+	_, err := ParseOrderedJSON([]byte(jsoncExample))
+	require.NotNil(t, err)
+
+	jobj, err := ParseOrderedJSONWithOptions([]byte(jsoncExample), ParseOptions{Dialect: DialectJSONC})
+	require.Nil(t, err)
+	root := jobj.(*OJsonMap)
+	require.Equal(t, []string{"// header"}, root.Comments)
+	require.Equal(t, []string{"/* first */"}, root.OrderedKV[0].Comments)
+	require.Equal(t, []string{"// about b"}, root.OrderedKV[1].Value.(*OJsonList).Comments)
+	require.Equal(t, 2, len(root.OrderedKV[1].Value.(*OJsonList).Items))
+	require.Equal(t, []string{"// trailing comma"}, root.OrderedKV[1].Value.(*OJsonList).ClosingComments)
+	require.Equal(t, "not // a comment", root.OrderedKV[2].Value.(*OJsonString).Value)
+	require.Equal(t, []string{"// before closing"}, root.ClosingComments)
+	require.Equal(t, Position{Line: 9, Column: 10, Offset: 140}, root.OrderedKV[2].Value.Position())
+
+	expected := `// header
+{
+    /* first */ "a": 1,
+    "b": // about b
+    [
+        true,
+        null
+        // trailing comma
+    ],
+    "c": "not // a comment"
+    // before closing
+}
+`
+	require.Equal(t, expected, JSONString(jobj))
+
+	reparsed, err := ParseOrderedJSONWithOptions([]byte(JSONString(jobj)), ParseOptions{Dialect: DialectJSONC})
+	require.Nil(t, err)
+	require.Equal(t, expected, JSONString(reparsed))
+}
+
+func TestJSONCErrors(t *testing.T) {
+	options := ParseOptions{Dialect: DialectJSONC}
+
+	_, err := ParseOrderedJSONWithOptions([]byte("[1,\n /* open"), options)
+	require.Equal(t, "2:2: unterminated comment", err.Error())
+
+	_, err = ParseOrderedJSONWithOptions([]byte(`[1,,]`), options)
+	require.NotNil(t, err)
+
+	_, err = ParseOrderedJSONWithOptions([]byte(`[,]`), options)
+	require.NotNil(t, err)
+
+	_, err = ParseOrderedJSONWithOptions([]byte(`{,}`), options)
+	require.NotNil(t, err)
+
+	_, err = ParseOrderedJSON([]byte(`{"a": 1,}`))
+	require.NotNil(t, err)
+}
+
+func TestJSONCConcrete(t *testing.T) {
+	doc, err := ParseOrderedJSONConcreteWithOptions([]byte(jsoncExample), ParseOptions{Dialect: DialectJSONC})
+	require.Nil(t, err)
+	require.Equal(t, jsoncExample, ConcreteJSONString(doc))
+
+	root := doc.Root.(*OJsonMap)
+	root.Put("d", &OJsonBool{Value: true, Comments: []string{"// new"}})
+	require.Equal(t, `// header
+{
+    /* first */ "a": 1,
+    "b": // about b
+        [
+            true,
+            null, // trailing comma
+        ],
+    "c": "not // a comment",
+    "d": // new
+    true,
+    // before closing
+}
+`, ConcreteJSONString(doc))
+}
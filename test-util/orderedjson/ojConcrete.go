@@ -0,0 +1,478 @@
+package orderedjson
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+)
+
+// ConcreteDocument is an ordered JSON tree that also keeps the concrete syntax it was parsed from:
+// whitespace, comments, the original spelling of strings and numbers and everything else between the tokens.
+// The tree can be freely modified, ConcreteJSONString only reformats the parts that changed.
+type ConcreteDocument struct {
+	Root OJsonObject
+
+	source []byte
+	nodes  map[OJsonObject]*concreteNode
+	kvs    map[*OJsonKeyValuePair]*concreteKV
+	items  map[OJsonObject]*concreteItem
+	indent string
+}
+
+// concreteNode is the original form of a parsed node.
+type concreteNode struct {
+	start, end    int
+	closing       string // text between the last element and the closing bracket, containers only
+	trailingComma bool   // the last element is followed by a comma, containers only
+
+	// shallow snapshot, to detect changes
+	value      string
+	boolValue  bool
+	origKVs    []*OJsonKeyValuePair
+	origKeys   []string
+	origValues []OJsonObject
+}
+
+// concreteKV is the original form of a map entry.
+type concreteKV struct {
+	parent   *OJsonMap
+	key      string
+	leading  string // text between the opening bracket or previous comma and the key
+	keyText  string // the key, as originally written
+	sep      string // text between the key and the value, including the colon
+	trailing string // text between the value and the following comma
+}
+
+// concreteItem is the original form of a list item.
+type concreteItem struct {
+	parent   *OJsonList
+	leading  string
+	trailing string
+}
+
+// ParseOrderedJSONConcrete parses JSON, like ParseOrderedJSON,
+// but also keeps all the information needed to write it back exactly as it was.
+func ParseOrderedJSONConcrete(input []byte) (*ConcreteDocument, error) {
+	return ParseOrderedJSONConcreteWithOptions(input, ParseOptions{})
+}
+
+// ParseOrderedJSONConcreteWithOptions is ParseOrderedJSONConcrete, with options.
+// Duplicate keys are always rejected: the entries that would be dropped could not be written back.
+func ParseOrderedJSONConcreteWithOptions(input []byte, options ParseOptions) (*ConcreteDocument, error) {
+	if options.DuplicateKeys != DuplicateKeysError {
+		return nil, errors.New("duplicate keys can only be rejected when keeping the concrete syntax")
+	}
+	root, err := ParseOrderedJSONWithOptions(input, options)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &ConcreteDocument{
+		Root:   root,
+		source: input,
+		nodes:  make(map[OJsonObject]*concreteNode),
+		kvs:    make(map[*OJsonKeyValuePair]*concreteKV),
+		items:  make(map[OJsonObject]*concreteItem),
+	}
+	if _, err := doc.register(root); err != nil {
+		return nil, err
+	}
+	doc.indent = detectIndentUnit(input)
+	return doc, nil
+}
+
+// register records the original form of a node and of all its descendants.
+// Yields the offset right after the node.
+func (doc *ConcreteDocument) register(jobj OJsonObject) (int, error) {
+	src := doc.source
+	start := jobj.Position().Offset
+	cn := &concreteNode{start: start}
+	doc.nodes[jobj] = cn
+
+	switch j := jobj.(type) {
+	case *OJsonMap:
+		cursor := start + 1
+		for _, kv := range j.OrderedKV {
+			keyEnd := scanStringEnd(src, kv.KeyPos.Offset)
+			valueStart := kv.Value.Position().Offset
+			valueEnd, err := doc.register(kv.Value)
+			if err != nil {
+				return 0, err
+			}
+			ckv := &concreteKV{
+				parent:  j,
+				key:     kv.Key,
+				leading: string(src[cursor:kv.KeyPos.Offset]),
+				keyText: string(src[kv.KeyPos.Offset:keyEnd]),
+				sep:     string(src[keyEnd:valueStart]),
+			}
+			doc.kvs[kv] = ckv
+			cursor = valueEnd
+			if next := skipTrivia(src, valueEnd); next < len(src) && src[next] == ',' {
+				ckv.trailing = string(src[valueEnd:next])
+				cursor = next + 1
+				cn.trailingComma = true
+			} else {
+				cn.trailingComma = false
+			}
+			cn.origKVs = append(cn.origKVs, kv)
+			cn.origKeys = append(cn.origKeys, kv.Key)
+			cn.origValues = append(cn.origValues, kv.Value)
+		}
+		closePos := skipTrivia(src, cursor)
+		if closePos >= len(src) || src[closePos] != '}' {
+			return 0, errors.New("inconsistent concrete syntax, '}' expected")
+		}
+		cn.closing = string(src[cursor:closePos])
+		cn.end = closePos + 1
+	case *OJsonList:
+		cursor := start + 1
+		for _, item := range j.Items {
+			itemEnd, err := doc.register(item)
+			if err != nil {
+				return 0, err
+			}
+			ci := &concreteItem{
+				parent:  j,
+				leading: string(src[cursor:item.Position().Offset]),
+			}
+			doc.items[item] = ci
+			cursor = itemEnd
+			if next := skipTrivia(src, itemEnd); next < len(src) && src[next] == ',' {
+				ci.trailing = string(src[itemEnd:next])
+				cursor = next + 1
+				cn.trailingComma = true
+			} else {
+				cn.trailingComma = false
+			}
+			cn.origValues = append(cn.origValues, item)
+		}
+		closePos := skipTrivia(src, cursor)
+		if closePos >= len(src) || src[closePos] != ']' {
+			return 0, errors.New("inconsistent concrete syntax, ']' expected")
+		}
+		cn.closing = string(src[cursor:closePos])
+		cn.end = closePos + 1
+	case *OJsonString:
+		cn.value = j.Value
+		cn.end = scanStringEnd(src, start)
+	case *OJsonNumber:
+		cn.value = j.Value
+		cn.end = scanLiteralEnd(src, start)
+	case *OJsonBool:
+		cn.boolValue = j.Value
+		cn.end = scanLiteralEnd(src, start)
+	case *OJsonNull:
+		cn.end = scanLiteralEnd(src, start)
+	default:
+		return 0, errors.New("unknown OJsonObject type")
+	}
+	return cn.end, nil
+}
+
+// scanStringEnd yields the offset right after the string literal starting at the given offset.
+func scanStringEnd(src []byte, start int) int {
+	escaped := false
+	for i := start + 1; i < len(src); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case src[i] == '\\':
+			escaped = true
+		case src[i] == '"':
+			return i + 1
+		}
+	}
+	return len(src)
+}
+
+// scanLiteralEnd yields the offset right after the number, boolean or null starting at the given offset.
+func scanLiteralEnd(src []byte, start int) int {
+	i := start
+	for i < len(src) && !isWhitespace(src[i]) && src[i] != ',' && src[i] != ']' && src[i] != '}' && src[i] != '/' {
+		i++
+	}
+	return i
+}
+
+// skipTrivia yields the offset of the first significant character, starting from the given offset.
+// Whitespace and comments are skipped.
+func skipTrivia(src []byte, start int) int {
+	i := start
+	for i < len(src) {
+		switch {
+		case isWhitespace(src[i]):
+			i++
+		case bytes.HasPrefix(src[i:], []byte("//")):
+			for i < len(src) && src[i] != '\n' && src[i] != '\r' {
+				i++
+			}
+		case bytes.HasPrefix(src[i:], []byte("/*")):
+			closing := bytes.Index(src[i+2:], []byte("*/"))
+			if closing == -1 {
+				return len(src)
+			}
+			i += 2 + closing + 2
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// detectIndentUnit yields the indentation of the first indented line, 4 spaces if there is none.
+func detectIndentUnit(src []byte) string {
+	lines := strings.Split(string(src), "\n")
+	for _, line := range lines {
+		indentLen := 0
+		for indentLen < len(line) && (line[indentLen] == ' ' || line[indentLen] == '\t') {
+			indentLen++
+		}
+		if indentLen > 0 && indentLen < len(line) {
+			return line[:indentLen]
+		}
+	}
+	return "    "
+}
+
+// lineIndent yields the indentation of the line containing the given offset.
+func lineIndent(src []byte, offset int) string {
+	lineStart := offset
+	for lineStart > 0 && src[lineStart-1] != '\n' {
+		lineStart--
+	}
+	i := lineStart
+	for i < len(src) && (src[i] == ' ' || src[i] == '\t') {
+		i++
+	}
+	return string(src[lineStart:i])
+}
+
+// ConcreteJSONString writes back a document parsed with ParseOrderedJSONConcrete.
+// Everything that was not modified is written exactly as it was in the original input.
+// Changed or new nodes are formatted like JSONString does, following the indentation of the original.
+func ConcreteJSONString(doc *ConcreteDocument) string {
+	var sb strings.Builder
+	rootNode, rootIsOriginal := doc.nodes[doc.Root]
+	if !rootIsOriginal {
+		doc.writeFormatted(&sb, doc.Root, "")
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	sb.Write(doc.source[:rootNode.start])
+	doc.writeConcrete(&sb, doc.Root, "")
+	sb.Write(doc.source[rootNode.end:])
+	return sb.String()
+}
+
+// unchanged yields true if neither the node, nor any of its descendants were modified since parsing.
+func (doc *ConcreteDocument) unchanged(jobj OJsonObject) bool {
+	cn, isOriginal := doc.nodes[jobj]
+	if !isOriginal {
+		return false
+	}
+	switch j := jobj.(type) {
+	case *OJsonMap:
+		if len(j.OrderedKV) != len(cn.origKVs) {
+			return false
+		}
+		for i, kv := range j.OrderedKV {
+			if kv != cn.origKVs[i] || kv.Key != cn.origKeys[i] || kv.Value != cn.origValues[i] {
+				return false
+			}
+			if !doc.unchanged(kv.Value) {
+				return false
+			}
+		}
+		return true
+	case *OJsonList:
+		if len(j.Items) != len(cn.origValues) {
+			return false
+		}
+		for i, item := range j.Items {
+			if item != cn.origValues[i] || !doc.unchanged(item) {
+				return false
+			}
+		}
+		return true
+	case *OJsonString:
+		return j.Value == cn.value
+	case *OJsonNumber:
+		return j.Value == cn.value
+	case *OJsonBool:
+		return j.Value == cn.boolValue
+	case *OJsonNull:
+		return true
+	default:
+		return false
+	}
+}
+
+func (doc *ConcreteDocument) writeConcrete(sb *strings.Builder, jobj OJsonObject, indent string) {
+	cn, isOriginal := doc.nodes[jobj]
+	if !isOriginal {
+		doc.writeFormatted(sb, jobj, indent)
+		return
+	}
+	if doc.unchanged(jobj) {
+		sb.Write(doc.source[cn.start:cn.end])
+		return
+	}
+
+	indent = lineIndent(doc.source, cn.start)
+	multiline := bytes.IndexByte(doc.source[cn.start:cn.end], '\n') >= 0
+	switch j := jobj.(type) {
+	case *OJsonMap:
+		childIndent := indent + doc.indent
+		for _, kv := range cn.origKVs {
+			if siblingIndent, found := indentAfterNewline(doc.kvs[kv].leading); found {
+				childIndent = siblingIndent
+				break
+			}
+		}
+		sb.WriteString("{")
+		for i, kv := range j.OrderedKV {
+			ckv, isOriginalKV := doc.kvs[kv]
+			if isOriginalKV && ckv.parent == j {
+				sb.WriteString(ckv.leading)
+				if kv.Key == ckv.key {
+					sb.WriteString(ckv.keyText)
+				} else {
+					writeJSONString(sb, kv.Key)
+				}
+				sb.WriteString(ckv.sep)
+				doc.writeConcrete(sb, kv.Value, childIndent)
+				sb.WriteString(ckv.trailing)
+			} else {
+				writeNewElementLeading(sb, i, multiline || len(cn.origKVs) == 0, childIndent)
+				writeComments(sb, kv.Comments, childIndent)
+				writeJSONString(sb, kv.Key)
+				sb.WriteString(": ")
+				doc.writeConcrete(sb, kv.Value, childIndent)
+			}
+			if i < len(j.OrderedKV)-1 || cn.trailingComma {
+				sb.WriteString(",")
+			}
+		}
+		writeClosing(sb, cn, len(j.OrderedKV), len(cn.origKVs), indent)
+		sb.WriteString("}")
+	case *OJsonList:
+		childIndent := indent + doc.indent
+		for _, item := range cn.origValues {
+			if siblingIndent, found := indentAfterNewline(doc.items[item].leading); found {
+				childIndent = siblingIndent
+				break
+			}
+		}
+		sb.WriteString("[")
+		for i, item := range j.Items {
+			ci, isOriginalItem := doc.items[item]
+			if isOriginalItem && ci.parent == j {
+				sb.WriteString(ci.leading)
+				doc.writeConcrete(sb, item, childIndent)
+				sb.WriteString(ci.trailing)
+			} else {
+				writeNewElementLeading(sb, i, multiline || len(cn.origValues) == 0, childIndent)
+				doc.writeConcrete(sb, item, childIndent)
+			}
+			if i < len(j.Items)-1 || cn.trailingComma {
+				sb.WriteString(",")
+			}
+		}
+		writeClosing(sb, cn, len(j.Items), len(cn.origValues), indent)
+		sb.WriteString("]")
+	default:
+		// changed scalar
+		sb.WriteString(scalarJSONString(jobj))
+	}
+}
+
+// indentAfterNewline yields the indentation of the last line of some whitespace, if it spans several lines.
+func indentAfterNewline(trivia string) (string, bool) {
+	lastNewline := strings.LastIndexByte(trivia, '\n')
+	if lastNewline == -1 {
+		return "", false
+	}
+	lastLine := trivia[lastNewline+1:]
+	if strings.Trim(lastLine, " \t") != "" {
+		return "", false
+	}
+	return lastLine, true
+}
+
+func writeNewElementLeading(sb *strings.Builder, index int, multiline bool, childIndent string) {
+	if multiline {
+		sb.WriteString("\n")
+		sb.WriteString(childIndent)
+	} else if index > 0 {
+		sb.WriteString(" ")
+	}
+}
+
+func writeClosing(sb *strings.Builder, cn *concreteNode, newSize int, origSize int, indent string) {
+	switch {
+	case newSize == 0:
+		if origSize == 0 {
+			sb.WriteString(cn.closing)
+		}
+	case origSize == 0:
+		sb.WriteString("\n")
+		sb.WriteString(indent)
+	default:
+		sb.WriteString(cn.closing)
+	}
+}
+
+// writeFormatted writes a new node in the style of JSONString, but with the indentation of the document.
+func (doc *ConcreteDocument) writeFormatted(sb *strings.Builder, jobj OJsonObject, indent string) {
+	if _, isOriginal := doc.nodes[jobj]; isOriginal {
+		// original nodes moved under new ones are kept as they were
+		doc.writeConcrete(sb, jobj, indent)
+		return
+	}
+
+	writeComments(sb, commentsOf(jobj), indent)
+	childIndent := indent + doc.indent
+	switch j := jobj.(type) {
+	case *OJsonMap:
+		if j.Size() == 0 {
+			sb.WriteString("{}")
+			return
+		}
+		sb.WriteString("{")
+		for i, kv := range j.OrderedKV {
+			sb.WriteString("\n")
+			sb.WriteString(childIndent)
+			writeComments(sb, kv.Comments, childIndent)
+			writeJSONString(sb, kv.Key)
+			sb.WriteString(": ")
+			doc.writeFormatted(sb, kv.Value, childIndent)
+			if i < len(j.OrderedKV)-1 {
+				sb.WriteString(",")
+			}
+		}
+		sb.WriteString("\n")
+		sb.WriteString(indent)
+		sb.WriteString("}")
+	case *OJsonList:
+		if len(j.Items) == 0 {
+			sb.WriteString("[]")
+			return
+		}
+		sb.WriteString("[")
+		for i, item := range j.Items {
+			sb.WriteString("\n")
+			sb.WriteString(childIndent)
+			doc.writeFormatted(sb, item, childIndent)
+			if i < len(j.Items)-1 {
+				sb.WriteString(",")
+			}
+		}
+		sb.WriteString("\n")
+		sb.WriteString(indent)
+		sb.WriteString("]")
+	default:
+		sb.WriteString(scalarJSONString(jobj))
+	}
+}
@@ -0,0 +1,113 @@
+package orderedjson
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const concreteExample = `  {
+  "name":"example",
+  "numbers": [ 1.50, -0, 2E3 ],
+  "nested": {
+      "a" : "A",
+      "b": true
+  },
+  "empty": {}
+}
+`
+
+func TestConcreteUnchanged(t *testing.T) {
+	input := []byte(concreteExample)
+	doc, err := ParseOrderedJSONConcrete(input)
+	require.Nil(t, err)
+	require.Equal(t, string(input), ConcreteJSONString(doc))
+}
+
+func TestConcreteLargeDocument(t *testing.T) {
+	// about 3 MB, parsing used to be quadratic in the size of the input
+	var sb strings.Builder
+	sb.WriteString("[\n")
+	for i := 0; i < 50000; i++ {
+		sb.WriteString("  // item\n  {\"key\": \"value\", \"list\": [1, 2, /* three */ 3]},\n")
+	}
+	sb.WriteString("  null\n]\n")
+	input := []byte(sb.String())
+
+	start := time.Now()
+	doc, err := ParseOrderedJSONConcreteWithOptions(input, ParseOptions{Dialect: DialectJSONC})
+	require.Nil(t, err)
+	require.Equal(t, string(input), ConcreteJSONString(doc))
+	require.True(t, time.Since(start) < 10*time.Second, "took %s", time.Since(start))
+}
+
+func TestConcreteDuplicateKeys(t *testing.T) {
+	inputs := []string{
+		`{"a": 1, "a": 2, "b": 3}`,
+		`{"a": 1, "b": 3, "a": 2}`,
+	}
+	for _, input := range inputs {
+		for _, duplicateKeys := range []DuplicateKeys{DuplicateKeysFirstWins, DuplicateKeysLastWins} {
+			_, err := ParseOrderedJSONConcreteWithOptions([]byte(input), ParseOptions{DuplicateKeys: duplicateKeys})
+			require.NotNil(t, err, input)
+		}
+
+		_, err := ParseOrderedJSONConcreteWithOptions([]byte(input), ParseOptions{})
+		var duplicateErr *DuplicateKeyError
+		require.True(t, errors.As(err, &duplicateErr), input)
+	}
+}
+
+func TestConcreteModified(t *testing.T) {
+	input := `{
+  "name":"example",
+  "numbers": [ 1.50, -0, 2E3 ],
+  "nested": {
+      "a" : "A",
+      "b": true
+  },
+  "empty": {}
+}
+`
+	doc, err := ParseOrderedJSONConcrete([]byte(input))
+	require.Nil(t, err)
+	root := doc.Root.(*OJsonMap)
+
+	// change a scalar, the rest of the list stays as it was
+	numbers := root.OrderedKV[1].Value.(*OJsonList)
+	numbers.Items[2] = &OJsonNumber{Value: "7"}
+
+	// remove an entry and add another one
+	nested := root.OrderedKV[2].Value.(*OJsonMap)
+	nested.OrderedKV = nested.OrderedKV[:1]
+	nested.Put("c", &OJsonString{Value: "new"})
+	nested.RefreshKeySet()
+
+	// fill an empty map
+	empty := root.OrderedKV[3].Value.(*OJsonMap)
+	list := &OJsonList{Items: []OJsonObject{&OJsonBool{Value: false}}}
+	empty.Put("x", list)
+
+	expected := `{
+  "name":"example",
+  "numbers": [ 1.50, -0, 7 ],
+  "nested": {
+      "a" : "A",
+      "c": "new"
+  },
+  "empty": {
+    "x": [
+      false
+    ]
+  }
+}
+`
+	require.Equal(t, expected, ConcreteJSONString(doc))
+
+	reparsed, err := ParseOrderedJSON([]byte(ConcreteJSONString(doc)))
+	require.Nil(t, err)
+	require.Equal(t, JSONString(doc.Root), JSONString(reparsed))
+}
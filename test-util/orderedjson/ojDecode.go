@@ -0,0 +1,333 @@
+package orderedjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	ojsonObjectType      = reflect.TypeOf((*OJsonObject)(nil)).Elem()
+	jsonUnmarshalerType  = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	bigIntType           = reflect.TypeOf(big.Int{})
+	emptyInterfaceType   = reflect.TypeOf((*interface{})(nil)).Elem()
+	errDecodeNotPointer  = errors.New("decode target must be a non-nil pointer")
+	errDecodeUnknownType = errors.New("unsupported decode target type")
+)
+
+// Decode fills a Go value from an ordered JSON tree, the way json.Unmarshal would,
+// except that unknown map keys are reported as errors instead of being ignored.
+//
+// Struct fields are matched by their json tag, or by name if untagged, case-insensitively as a fallback.
+// Fields tagged `json:"-"` are never set. Fields of embedded structs are promoted, as in encoding/json.
+// Other supported targets are pointers, maps with string keys, slices, arrays, strings, bools,
+// all integer and float types, big.Int, interface{} (see ToInterface), OJsonObject (which gets the node itself)
+// and types implementing json.Unmarshaler.
+//
+// Errors are reported with the JSON pointer of the offending value and, if known, its position in the input.
+func Decode(jobj OJsonObject, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errDecodeNotPointer
+	}
+	return decodeValue(Pointer{}, jobj, v.Elem())
+}
+
+// decodeError builds an error about the value at the given path.
+func decodeError(path Pointer, pos Position, format string, args ...interface{}) error {
+	err := fmt.Errorf("cannot decode %s: %s", pathForMessage(path), fmt.Sprintf(format, args...))
+	if pos.IsValid() {
+		return ErrorAt(pos, err)
+	}
+	return err
+}
+
+func pathForMessage(path Pointer) string {
+	if len(path) == 0 {
+		return "root"
+	}
+	return path.String()
+}
+
+func decodeValue(path Pointer, jobj OJsonObject, v reflect.Value) error {
+	// the ordered JSON node itself
+	if v.Type() == ojsonObjectType {
+		v.Set(reflect.ValueOf(jobj))
+		return nil
+	}
+	if v.Kind() == reflect.Ptr && reflect.TypeOf(jobj) == v.Type() {
+		v.Set(reflect.ValueOf(jobj))
+		return nil
+	}
+
+	_, isNull := jobj.(*OJsonNull)
+	if v.Kind() == reflect.Ptr {
+		if isNull {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeValue(path, jobj, v.Elem())
+	}
+
+	// before json.Unmarshaler, to also accept forms like 1e18
+	if v.Type() == bigIntType && !isNull {
+		number, isNumber := jobj.(*OJsonNumber)
+		if !isNumber {
+			return decodeError(path, jobj.Position(), "expected a number, got %s", typeName(jobj))
+		}
+		value, ok := number.BigInt()
+		if !ok {
+			return decodeError(path, jobj.Position(), "not an integer: %s", number.Value)
+		}
+		v.Set(reflect.ValueOf(*value))
+		return nil
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(jsonUnmarshalerType) {
+		serialized, _ := marshalCompact(jobj)
+		if err := v.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(serialized); err != nil {
+			return decodeError(path, jobj.Position(), "%s", err.Error())
+		}
+		return nil
+	}
+
+	if v.Type() == emptyInterfaceType {
+		if isNull {
+			v.Set(reflect.Zero(v.Type()))
+		} else {
+			v.Set(reflect.ValueOf(ToInterface(jobj)))
+		}
+		return nil
+	}
+
+	if isNull {
+		// same as encoding/json: null leaves other values unchanged
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return decodeStruct(path, jobj, v)
+	case reflect.Map:
+		return decodeMap(path, jobj, v)
+	case reflect.Slice, reflect.Array:
+		return decodeList(path, jobj, v)
+	case reflect.String:
+		str, isString := jobj.(*OJsonString)
+		if !isString {
+			return decodeError(path, jobj.Position(), "expected a string, got %s", typeName(jobj))
+		}
+		v.SetString(str.Value)
+		return nil
+	case reflect.Bool:
+		b, isBool := jobj.(*OJsonBool)
+		if !isBool {
+			return decodeError(path, jobj.Position(), "expected a bool, got %s", typeName(jobj))
+		}
+		v.SetBool(b.Value)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return decodeNumber(path, jobj, v)
+	default:
+		return decodeError(path, jobj.Position(), "%s: %s", errDecodeUnknownType.Error(), v.Type().String())
+	}
+}
+
+func decodeNumber(path Pointer, jobj OJsonObject, v reflect.Value) error {
+	number, isNumber := jobj.(*OJsonNumber)
+	if !isNumber {
+		return decodeError(path, jobj.Position(), "expected a number, got %s", typeName(jobj))
+	}
+	bits := v.Type().Bits()
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(number.Value, bits)
+		if err != nil {
+			return decodeError(path, jobj.Position(), "number out of range for %s: %s", v.Type().String(), number.Value)
+		}
+		v.SetFloat(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := strconv.ParseUint(number.Value, 10, bits)
+		if err != nil {
+			return decodeError(path, jobj.Position(), "invalid %s: %s", v.Type().String(), number.Value)
+		}
+		v.SetUint(value)
+	default:
+		value, err := strconv.ParseInt(number.Value, 10, bits)
+		if err != nil {
+			return decodeError(path, jobj.Position(), "invalid %s: %s", v.Type().String(), number.Value)
+		}
+		v.SetInt(value)
+	}
+	return nil
+}
+
+func decodeList(path Pointer, jobj OJsonObject, v reflect.Value) error {
+	list, isList := jobj.(*OJsonList)
+	if !isList {
+		return decodeError(path, jobj.Position(), "expected a list, got %s", typeName(jobj))
+	}
+	if v.Kind() == reflect.Array {
+		if len(list.Items) != v.Len() {
+			return decodeError(path, jobj.Position(), "expected %d items, got %d", v.Len(), len(list.Items))
+		}
+	} else {
+		v.Set(reflect.MakeSlice(v.Type(), len(list.Items), len(list.Items)))
+	}
+	for i, item := range list.Items {
+		if err := decodeValue(path.AppendIndex(i), item, v.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeMap(path Pointer, jobj OJsonObject, v reflect.Value) error {
+	m, isMap := jobj.(*OJsonMap)
+	if !isMap {
+		return decodeError(path, jobj.Position(), "expected a map, got %s", typeName(jobj))
+	}
+	if v.Type().Key().Kind() != reflect.String {
+		return decodeError(path, jobj.Position(), "map keys must be strings, not %s", v.Type().Key().String())
+	}
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+	for _, kv := range m.OrderedKV {
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if err := decodeValue(path.Append(kv.Key), kv.Value, elem); err != nil {
+			return err
+		}
+		v.SetMapIndex(reflect.ValueOf(kv.Key).Convert(v.Type().Key()), elem)
+	}
+	return nil
+}
+
+// structFieldIndex yields the index path of the field that should receive a key, nil if none.
+// Fields of embedded structs are promoted, like encoding/json does:
+// shallower fields win, and fields with the same name at the same depth cancel each other out.
+func structFieldIndex(t reflect.Type, key string) []int {
+	var caseInsensitiveMatch []int
+	visited := map[reflect.Type]bool{t: true}
+	level := []structFieldCandidate{{typ: t}}
+	for len(level) > 0 {
+		var next []structFieldCandidate
+		var exactMatches, caseInsensitiveMatches [][]int
+		for _, candidate := range level {
+			for i := 0; i < candidate.typ.NumField(); i++ {
+				field := candidate.typ.Field(i)
+				index := append(append([]int{}, candidate.index...), i)
+				name, tagged, skip := structFieldName(field)
+				if skip {
+					continue
+				}
+				if embedded, isEmbedded := embeddedStructType(field); isEmbedded && !tagged {
+					if !visited[embedded] {
+						visited[embedded] = true
+						next = append(next, structFieldCandidate{typ: embedded, index: index})
+					}
+					continue
+				}
+				if field.PkgPath != "" {
+					continue // unexported
+				}
+				if name == key {
+					exactMatches = append(exactMatches, index)
+				} else if strings.EqualFold(name, key) {
+					caseInsensitiveMatches = append(caseInsensitiveMatches, index)
+				}
+			}
+		}
+		if len(exactMatches) > 0 {
+			return uniqueFieldIndex(exactMatches)
+		}
+		if caseInsensitiveMatch == nil && len(caseInsensitiveMatches) > 0 {
+			caseInsensitiveMatch = uniqueFieldIndex(caseInsensitiveMatches)
+		}
+		level = next
+	}
+	return caseInsensitiveMatch
+}
+
+type structFieldCandidate struct {
+	typ   reflect.Type
+	index []int
+}
+
+// structFieldName yields the key of a field, whether it comes from a tag, and whether the field is ignored.
+func structFieldName(field reflect.StructField) (string, bool, bool) {
+	if tag, hasTag := field.Tag.Lookup("json"); hasTag {
+		tagName := strings.Split(tag, ",")[0]
+		if tagName == "-" {
+			return "", false, true
+		}
+		if tagName != "" {
+			return tagName, true, false
+		}
+	}
+	return field.Name, false, false
+}
+
+// embeddedStructType yields the struct type of an embedded field, whose fields get promoted.
+// Unexported embedded pointers are left out, they could not be allocated.
+func embeddedStructType(field reflect.StructField) (reflect.Type, bool) {
+	if !field.Anonymous {
+		return nil, false
+	}
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		if field.PkgPath != "" {
+			return nil, false
+		}
+		t = t.Elem()
+	}
+	return t, t.Kind() == reflect.Struct
+}
+
+// uniqueFieldIndex yields the only match at some depth, nil if the name is ambiguous.
+func uniqueFieldIndex(matches [][]int) []int {
+	if len(matches) > 1 {
+		return nil
+	}
+	return matches[0]
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex, allocating embedded pointers on the way.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, fieldIndex := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(fieldIndex)
+	}
+	return v
+}
+
+func decodeStruct(path Pointer, jobj OJsonObject, v reflect.Value) error {
+	m, isMap := jobj.(*OJsonMap)
+	if !isMap {
+		return decodeError(path, jobj.Position(), "expected a map, got %s", typeName(jobj))
+	}
+	for _, kv := range m.OrderedKV {
+		fieldIndex := structFieldIndex(v.Type(), kv.Key)
+		if fieldIndex == nil {
+			return decodeError(path, kv.KeyPos, "unknown key: %s", kv.Key)
+		}
+		if err := decodeValue(path.Append(kv.Key), kv.Value, fieldByIndex(v, fieldIndex)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package orderedjson
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// ChangeKind classifies the differences found by Diff.
+type ChangeKind int
+
+const (
+	// ChangeAdded means that a map key or list item only exists in the new tree.
+	ChangeAdded ChangeKind = iota
+
+	// ChangeRemoved means that a map key or list item only exists in the old tree.
+	ChangeRemoved
+
+	// ChangeModified means that a value was replaced, either by another type of value or by a different scalar.
+	ChangeModified
+
+	// ChangeReordered means that the keys common to both versions of a map are in a different order.
+	ChangeReordered
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	case ChangeReordered:
+		return "reordered"
+	default:
+		return "unknown"
+	}
+}
+
+// Change is a difference between two ordered JSON trees.
+// Old is nil for added values, New is nil for removed values.
+// For reordered maps, Old and New are the two versions of the map.
+type Change struct {
+	Kind ChangeKind
+	Path Pointer
+	Old  OJsonObject
+	New  OJsonObject
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s %s", c.Kind.String(), c.Path.String())
+}
+
+// Diff compares two ordered JSON trees structurally, ignoring formatting, positions and comments.
+// Changes are reported in document order.
+// Lists are compared item by item, so an insertion also shows up as modifications of the following items.
+func Diff(oldObj OJsonObject, newObj OJsonObject) []Change {
+	return diff(Pointer{}, oldObj, newObj, nil)
+}
+
+func diff(path Pointer, oldObj OJsonObject, newObj OJsonObject, changes []Change) []Change {
+	switch oldJ := oldObj.(type) {
+	case *OJsonMap:
+		newJ, isMap := newObj.(*OJsonMap)
+		if !isMap {
+			break
+		}
+		var oldCommonKeys, newCommonKeys []string
+		for _, kv := range oldJ.OrderedKV {
+			newValue, found := newJ.Get(kv.Key)
+			if !found {
+				changes = append(changes, Change{Kind: ChangeRemoved, Path: path.Append(kv.Key), Old: kv.Value})
+				continue
+			}
+			oldCommonKeys = append(oldCommonKeys, kv.Key)
+			changes = diff(path.Append(kv.Key), kv.Value, newValue, changes)
+		}
+		for _, kv := range newJ.OrderedKV {
+			if _, found := oldJ.Get(kv.Key); !found {
+				changes = append(changes, Change{Kind: ChangeAdded, Path: path.Append(kv.Key), New: kv.Value})
+				continue
+			}
+			newCommonKeys = append(newCommonKeys, kv.Key)
+		}
+		for i := range oldCommonKeys {
+			if oldCommonKeys[i] != newCommonKeys[i] {
+				changes = append(changes, Change{Kind: ChangeReordered, Path: path, Old: oldObj, New: newObj})
+				break
+			}
+		}
+		return changes
+	case *OJsonList:
+		newJ, isList := newObj.(*OJsonList)
+		if !isList {
+			break
+		}
+		for i, item := range oldJ.Items {
+			if i < len(newJ.Items) {
+				changes = diff(path.AppendIndex(i), item, newJ.Items[i], changes)
+			} else {
+				changes = append(changes, Change{Kind: ChangeRemoved, Path: path.AppendIndex(i), Old: item})
+			}
+		}
+		for i := len(oldJ.Items); i < len(newJ.Items); i++ {
+			changes = append(changes, Change{Kind: ChangeAdded, Path: path.AppendIndex(i), New: newJ.Items[i]})
+		}
+		return changes
+	default:
+		if scalarsEqual(oldObj, newObj) {
+			return changes
+		}
+	}
+	return append(changes, Change{Kind: ChangeModified, Path: path, Old: oldObj, New: newObj})
+}
+
+// Equal checks whether two ordered JSON trees have the same contents, in the same order.
+// Formatting, positions and comments are ignored.
+// Numbers are compared by value, so 1000 and 1e3 are equal.
+func Equal(a OJsonObject, b OJsonObject) bool {
+	switch aJ := a.(type) {
+	case *OJsonMap:
+		bJ, isMap := b.(*OJsonMap)
+		if !isMap || len(aJ.OrderedKV) != len(bJ.OrderedKV) {
+			return false
+		}
+		for i, kv := range aJ.OrderedKV {
+			if kv.Key != bJ.OrderedKV[i].Key || !Equal(kv.Value, bJ.OrderedKV[i].Value) {
+				return false
+			}
+		}
+		return true
+	case *OJsonList:
+		bJ, isList := b.(*OJsonList)
+		if !isList || len(aJ.Items) != len(bJ.Items) {
+			return false
+		}
+		for i, item := range aJ.Items {
+			if !Equal(item, bJ.Items[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return scalarsEqual(a, b)
+	}
+}
+
+func scalarsEqual(a OJsonObject, b OJsonObject) bool {
+	switch aJ := a.(type) {
+	case *OJsonString:
+		bJ, isString := b.(*OJsonString)
+		return isString && aJ.Value == bJ.Value
+	case *OJsonBool:
+		bJ, isBool := b.(*OJsonBool)
+		return isBool && aJ.Value == bJ.Value
+	case *OJsonNull:
+		_, isNull := b.(*OJsonNull)
+		return isNull
+	case *OJsonNumber:
+		bJ, isNumber := b.(*OJsonNumber)
+		if !isNumber {
+			return false
+		}
+		if aJ.Value == bJ.Value {
+			return true
+		}
+		aRat, aOk := big.NewRat(0, 1).SetString(aJ.Value)
+		bRat, bOk := big.NewRat(0, 1).SetString(bJ.Value)
+		return aOk && bOk && aRat.Cmp(bRat) == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,89 @@
+package orderedjson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustParse(t *testing.T, input string) OJsonObject {
+	jobj, err := ParseOrderedJSON([]byte(input))
+	require.Nil(t, err)
+	return jobj
+}
+
+func TestDiff(t *testing.T) {
+	oldObj := mustParse(t, `{"a": 1, "b": [1, 2, 3], "c": {"x": "1", "y": "2"}, "d": true}`)
+	newObj := mustParse(t, `{"a": 1e0, "b": [1, 5], "c": {"y": "2", "x": "1", "z": null}, "e": "new"}`)
+
+	var changes []string
+	for _, change := range Diff(oldObj, newObj) {
+		changes = append(changes, change.String())
+	}
+	require.Equal(t, []string{
+		"modified /b/1",
+		"removed /b/2",
+		"added /c/z",
+		"reordered /c",
+		"removed /d",
+		"added /e",
+	}, changes)
+
+	require.Equal(t, 0, len(Diff(oldObj, Clone(oldObj))))
+	require.True(t, Equal(oldObj, Clone(oldObj)))
+	require.False(t, Equal(oldObj, newObj))
+}
+
+func TestMergePatch(t *testing.T) {
+	target := mustParse(t, `{"title": "Goodbye!", "author": {"givenName": "John", "familyName": "Doe"}, "tags": ["example", "sample"], "content": "text"}`)
+	patch := mustParse(t, `{"title": "Hello!", "phoneNumber": "+01-123-456-7890", "author": {"familyName": null}, "tags": ["example"]}`)
+	expected := mustParse(t, `{"title": "Hello!", "author": {"givenName": "John"}, "tags": ["example"], "content": "text", "phoneNumber": "+01-123-456-7890"}`)
+
+	result := MergePatch(target, patch)
+	require.Equal(t, JSONString(expected), JSONString(result))
+
+	// the target is left unchanged
+	require.Equal(t, 4, len(target.(*OJsonMap).OrderedKV))
+
+	require.Equal(t, JSONString(mustParse(t, `["c"]`)), JSONString(MergePatch(mustParse(t, `{"a": "b"}`), mustParse(t, `["c"]`))))
+	require.Equal(t, JSONString(mustParse(t, `{"a": {"bb": {}}}`)), JSONString(MergePatch(mustParse(t, `{"a": "b"}`), mustParse(t, `{"a": {"bb": {"ccc": null}}}`))))
+}
+
+func TestApplyPatch(t *testing.T) {
+	target := mustParse(t, `{"baz": "qux", "foo": "bar", "list": [1, 2]}`)
+	patch := mustParse(t, `[
+		{"op": "replace", "path": "/baz", "value": "boo"},
+		{"op": "add", "path": "/hello", "value": ["world"]},
+		{"op": "remove", "path": "/foo"},
+		{"op": "add", "path": "/list/1", "value": 5},
+		{"op": "add", "path": "/list/-", "value": 9},
+		{"op": "move", "from": "/hello/0", "path": "/moved"},
+		{"op": "copy", "from": "/list", "path": "/copied"},
+		{"op": "test", "path": "/list/0", "value": 1.0}
+	]`)
+	result, err := ApplyPatch(target, patch)
+	require.Nil(t, err)
+	expected := mustParse(t, `{"baz": "boo", "list": [1, 5, 2, 9], "hello": [], "moved": "world", "copied": [1, 5, 2, 9]}`)
+	require.Equal(t, JSONString(expected), JSONString(result))
+
+	// the target is left unchanged
+	require.Equal(t, "qux", target.(*OJsonMap).OrderedKV[0].Value.(*OJsonString).Value)
+
+	failing := []string{
+		`[{"op": "test", "path": "/baz", "value": "boo"}]`,
+		`[{"op": "remove", "path": "/missing"}]`,
+		`[{"op": "replace", "path": "/list/2", "value": 1}]`,
+		`[{"op": "add", "path": "/list/3", "value": 1}]`,
+		`[{"op": "move", "from": "/list", "path": "/list/0"}]`,
+		`[{"op": "unknown", "path": "/baz"}]`,
+		`[{"op": "add", "path": "/baz"}]`,
+		`{"op": "add", "path": "/baz", "value": 1}`,
+	}
+	for _, patchStr := range failing {
+		_, err := ApplyPatch(target, mustParse(t, patchStr))
+		require.NotNil(t, err, patchStr)
+	}
+
+	_, err = ApplyPatch(target, mustParse(t, `[{"op": "replace", "path": "/baz", "value": 1}, {"op": "remove", "path": "/missing"}]`))
+	require.Equal(t, "JSON patch operation 1: cannot resolve /missing: key not found: missing", err.Error())
+}
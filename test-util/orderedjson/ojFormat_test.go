@@ -0,0 +1,77 @@
+package orderedjson
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const formatExample = `{
+	"b": [1, 2, {"y": "2", "x": "1"}],
+	"a": {},
+	"c": { "long": ["aaaaaaaaaaaaaaaaaaaa", "bbbbbbbbbbbbbbbbbbbb", "cccccccccccccccccccc"] }
+}`
+
+func TestFormatOptions(t *testing.T) {
+	jobj, err := ParseOrderedJSON([]byte(formatExample))
+	require.Nil(t, err)
+
+	require.Equal(t,
+		`{"b":[1,2,{"y":"2","x":"1"}],"a":{},"c":{"long":["aaaaaaaaaaaaaaaaaaaa","bbbbbbbbbbbbbbbbbbbb","cccccccccccccccccccc"]}}`+"\n",
+		JSONStringWithOptions(jobj, FormatOptions{Compact: true}))
+
+	require.Equal(t,
+		`{"a":{},"b":[1,2,{"x":"1","y":"2"}],"c":{"long":["aaaaaaaaaaaaaaaaaaaa","bbbbbbbbbbbbbbbbbbbb","cccccccccccccccccccc"]}}`+"\n",
+		JSONStringWithOptions(jobj, FormatOptions{Compact: true, SortKeys: true}))
+
+	require.Equal(t, `{
+  "b": [1, 2, {"y": "2", "x": "1"}],
+  "a": {},
+  "c": {
+    "long": [
+      "aaaaaaaaaaaaaaaaaaaa",
+      "bbbbbbbbbbbbbbbbbbbb",
+      "cccccccccccccccccccc"
+    ]
+  }
+}
+`, JSONStringWithOptions(jobj, FormatOptions{IndentWidth: 2, MaxLineWidth: 40}))
+
+	require.True(t, strings.HasPrefix(
+		JSONStringWithOptions(jobj, FormatOptions{UseTabs: true}),
+		"{\n\t\"b\": [\n\t\t1,\n\t\t2,\n\t\t{\n\t\t\t\"y\": \"2\",\n\t\t\t\"x\": \"1\"\n\t\t}\n\t],\n\t\"a\": {},\n"))
+
+	var buf bytes.Buffer
+	require.Nil(t, WriteJSON(&buf, jobj, FormatOptions{}))
+	require.Equal(t, JSONString(jobj), buf.String())
+}
+
+func TestFormatKeepsCommentedContainersMultiline(t *testing.T) {
+	jobj, err := ParseOrderedJSONWithOptions([]byte(`[1, /* two */ 2]`), ParseOptions{Dialect: DialectJSONC})
+	require.Nil(t, err)
+	require.Equal(t, "[\n    1,\n    /* two */ 2\n]\n", JSONStringWithOptions(jobj, FormatOptions{MaxLineWidth: 80}))
+	require.Equal(t, "[1,2]\n", JSONStringWithOptions(jobj, FormatOptions{Compact: true}))
+}
+
+func TestFormatDeepTreeWithLineWidth(t *testing.T) {
+	// every level used to be formatted in full by each of its ancestors
+	leaf := strings.Repeat("x", 1000)
+	var root OJsonObject = &OJsonString{Value: leaf}
+	for i := 0; i < 1000; i++ {
+		root = &OJsonList{Items: []OJsonObject{&OJsonString{Value: leaf}, root}}
+	}
+	start := time.Now()
+	formatted := JSONStringWithOptions(root, FormatOptions{IndentWidth: 1, MaxLineWidth: 80})
+	require.True(t, time.Since(start) < 5*time.Second, "took %s", time.Since(start))
+
+	parsed, err := ParseOrderedJSONWithOptions([]byte(formatted), ParseOptions{MaxDepth: 2000})
+	require.Nil(t, err)
+	require.Equal(t, JSONString(root), JSONString(parsed))
+
+	// small lists still fit on one line, next to long ones
+	root = &OJsonList{Items: []OJsonObject{&OJsonList{Items: []OJsonObject{&OJsonNumber{Value: "1"}}}, &OJsonString{Value: leaf}}}
+	require.Equal(t, "[\n [1],\n \""+leaf+"\"\n]\n", JSONStringWithOptions(root, FormatOptions{IndentWidth: 1, MaxLineWidth: 80}))
+}
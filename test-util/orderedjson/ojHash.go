@@ -0,0 +1,94 @@
+package orderedjson
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// Fingerprint is a SHA-256 hash of the canonical encoding of a JSON tree.
+type Fingerprint [sha256.Size]byte
+
+// String yields the fingerprint in hex.
+func (f Fingerprint) String() string {
+	return hex.EncodeToString(f[:])
+}
+
+// Hash yields a fingerprint of the contents of a JSON tree.
+// Formatting, comments, string escapes and the way numbers are written (e.g. 1e3 and 1000) do not matter,
+// but the order of map keys does: two trees have the same hash exactly when Equal says they are equal.
+// A nil object hashes like null.
+func Hash(jobj OJsonObject) Fingerprint {
+	return hashCanonical(jobj, false)
+}
+
+// HashUnordered is like Hash, but ignores the order of map keys. The order of list items still matters.
+func HashUnordered(jobj OJsonObject) Fingerprint {
+	return hashCanonical(jobj, true)
+}
+
+func hashCanonical(jobj OJsonObject, sortKeys bool) Fingerprint {
+	h := sha256.New()
+	w := bufio.NewWriter(h)
+	writeCanonical(w, jobj, sortKeys)
+	_ = w.Flush() // writing to a hash never fails
+
+	var result Fingerprint
+	copy(result[:], h.Sum(nil))
+	return result
+}
+
+// writeCanonical writes compact JSON, with numbers normalized to a fraction, e.g. 3/2 for 1.5.
+// The output is only used for hashing, so it does not need to be valid JSON.
+func writeCanonical(w *bufio.Writer, jobj OJsonObject, sortKeys bool) {
+	switch j := jobj.(type) {
+	case *OJsonMap:
+		orderedKV := j.OrderedKV
+		if sortKeys {
+			orderedKV = append([]*OJsonKeyValuePair{}, orderedKV...)
+			sort.SliceStable(orderedKV, func(i, k int) bool {
+				return orderedKV[i].Key < orderedKV[k].Key
+			})
+		}
+		_ = w.WriteByte('{')
+		for i, kv := range orderedKV {
+			if i > 0 {
+				_ = w.WriteByte(',')
+			}
+			writeCanonicalString(w, kv.Key)
+			_ = w.WriteByte(':')
+			writeCanonical(w, kv.Value, sortKeys)
+		}
+		_ = w.WriteByte('}')
+	case *OJsonList:
+		_ = w.WriteByte('[')
+		for i, item := range j.Items {
+			if i > 0 {
+				_ = w.WriteByte(',')
+			}
+			writeCanonical(w, item, sortKeys)
+		}
+		_ = w.WriteByte(']')
+	case *OJsonString:
+		writeCanonicalString(w, j.Value)
+	case *OJsonNumber:
+		if rat, ok := big.NewRat(0, 1).SetString(j.Value); ok {
+			_, _ = w.WriteString(rat.RatString())
+		} else {
+			_, _ = w.WriteString(j.Value)
+		}
+	case nil:
+		_, _ = w.WriteString("null")
+	default:
+		_, _ = w.WriteString(scalarJSONString(jobj))
+	}
+}
+
+func writeCanonicalString(w *bufio.Writer, value string) {
+	var sb strings.Builder
+	writeJSONString(&sb, value)
+	_, _ = w.WriteString(sb.String())
+}
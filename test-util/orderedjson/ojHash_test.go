@@ -0,0 +1,34 @@
+package orderedjson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHash(t *testing.T) {
+	parse := func(input string) OJsonObject {
+		jobj, err := ParseOrderedJSONWithOptions([]byte(input), ParseOptions{Dialect: DialectJSONC})
+		require.Nil(t, err)
+		return jobj
+	}
+
+	a := parse(`{"a": [1, "x"], "b": {"c": true, "d": null}}`)
+	formatted := parse(`{
+		// comments and formatting do not matter
+		"a": [ 1.0e0, "x" ],
+		"b": { "c": true, "d": null }
+	}`)
+	reordered := parse(`{"b": {"d": null, "c": true}, "a": [1, "x"]}`)
+	different := parse(`{"a": ["x", 1], "b": {"c": true, "d": null}}`)
+
+	require.Equal(t, Hash(a), Hash(formatted))
+	require.NotEqual(t, Hash(a), Hash(reordered))
+	require.NotEqual(t, Hash(a), Hash(different))
+
+	require.Equal(t, HashUnordered(a), HashUnordered(reordered))
+	require.NotEqual(t, HashUnordered(a), HashUnordered(different))
+
+	require.NotEqual(t, Hash(&OJsonString{Value: "1"}), Hash(&OJsonNumber{Value: "1"}))
+	require.Equal(t, 64, len(Hash(a).String()))
+}
@@ -0,0 +1,226 @@
+package orderedjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// marshalCompact yields the compact JSON form, as expected by encoding/json.
+// Comments are not included.
+func marshalCompact(j OJsonObject) ([]byte, error) {
+	return []byte(strings.TrimSuffix(JSONStringWithOptions(j, FormatOptions{Compact: true}), "\n")), nil
+}
+
+// MarshalJSON implements json.Marshaler. Keys keep their order.
+func (j *OJsonMap) MarshalJSON() ([]byte, error) {
+	return marshalCompact(j)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (j *OJsonList) MarshalJSON() ([]byte, error) {
+	return marshalCompact(j)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (j *OJsonString) MarshalJSON() ([]byte, error) {
+	return marshalCompact(j)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (j *OJsonBool) MarshalJSON() ([]byte, error) {
+	return marshalCompact(j)
+}
+
+// MarshalJSON implements json.Marshaler. The original literal is kept as it is.
+func (j *OJsonNumber) MarshalJSON() ([]byte, error) {
+	return marshalCompact(j)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (j *OJsonNull) MarshalJSON() ([]byte, error) {
+	return marshalCompact(j)
+}
+
+// unmarshalNode parses the input and checks that it yields the expected node type.
+// As is the convention for json.Unmarshaler, a null input leaves the node unchanged.
+func unmarshalNode(data []byte, expected string) (OJsonObject, error) {
+	jobj, err := ParseOrderedJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, isNull := jobj.(*OJsonNull); isNull {
+		return nil, nil
+	}
+	if typeName(jobj) != expected {
+		return nil, fmt.Errorf("cannot unmarshal JSON %s into ordered JSON %s", typeName(jobj), expected)
+	}
+	return jobj, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Keys keep their order.
+func (j *OJsonMap) UnmarshalJSON(data []byte) error {
+	jobj, err := unmarshalNode(data, "map")
+	if jobj != nil {
+		*j = *jobj.(*OJsonMap)
+	}
+	return err
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *OJsonList) UnmarshalJSON(data []byte) error {
+	jobj, err := unmarshalNode(data, "list")
+	if jobj != nil {
+		*j = *jobj.(*OJsonList)
+	}
+	return err
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *OJsonString) UnmarshalJSON(data []byte) error {
+	jobj, err := unmarshalNode(data, "string")
+	if jobj != nil {
+		*j = *jobj.(*OJsonString)
+	}
+	return err
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *OJsonBool) UnmarshalJSON(data []byte) error {
+	jobj, err := unmarshalNode(data, "bool")
+	if jobj != nil {
+		*j = *jobj.(*OJsonBool)
+	}
+	return err
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *OJsonNumber) UnmarshalJSON(data []byte) error {
+	jobj, err := unmarshalNode(data, "number")
+	if jobj != nil {
+		*j = *jobj.(*OJsonNumber)
+	}
+	return err
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *OJsonNull) UnmarshalJSON(data []byte) error {
+	_, err := unmarshalNode(data, "null")
+	return err
+}
+
+// typeName yields the JSON type of an object, for error messages.
+func typeName(jobj OJsonObject) string {
+	switch jobj.(type) {
+	case *OJsonMap:
+		return "map"
+	case *OJsonList:
+		return "list"
+	case *OJsonString:
+		return "string"
+	case *OJsonBool:
+		return "bool"
+	case *OJsonNumber:
+		return "number"
+	case *OJsonNull:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", jobj)
+	}
+}
+
+// ToInterface converts an ordered JSON tree to the generic form used by encoding/json:
+// map[string]interface{}, []interface{}, string, bool, json.Number and nil.
+// Numbers become json.Number, so no precision is lost.
+// Note that the key order is lost in the conversion.
+func ToInterface(jobj OJsonObject) interface{} {
+	switch j := jobj.(type) {
+	case *OJsonMap:
+		result := make(map[string]interface{}, len(j.OrderedKV))
+		for _, kv := range j.OrderedKV {
+			result[kv.Key] = ToInterface(kv.Value)
+		}
+		return result
+	case *OJsonList:
+		result := make([]interface{}, len(j.Items))
+		for i, item := range j.Items {
+			result[i] = ToInterface(item)
+		}
+		return result
+	case *OJsonString:
+		return j.Value
+	case *OJsonBool:
+		return j.Value
+	case *OJsonNumber:
+		return json.Number(j.Value)
+	default:
+		return nil
+	}
+}
+
+// FromInterface converts a Go value to an ordered JSON tree.
+// Generic maps are written with their keys sorted, since Go maps have no order.
+// Any other value, e.g. a struct, is converted via encoding/json,
+// so struct fields keep their declaration order and json tags are respected.
+func FromInterface(value interface{}) (OJsonObject, error) {
+	switch v := value.(type) {
+	case nil:
+		return &OJsonNull{}, nil
+	case OJsonObject:
+		return Clone(v), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		result := NewMap()
+		for _, key := range keys {
+			converted, err := FromInterface(v[key])
+			if err != nil {
+				return nil, err
+			}
+			result.Put(key, converted)
+		}
+		return result, nil
+	case []interface{}:
+		result := &OJsonList{Items: make([]OJsonObject, len(v))}
+		for i, item := range v {
+			converted, err := FromInterface(item)
+			if err != nil {
+				return nil, err
+			}
+			result.Items[i] = converted
+		}
+		return result, nil
+	case string:
+		return &OJsonString{Value: v}, nil
+	case bool:
+		return &OJsonBool{Value: v}, nil
+	case json.Number:
+		if !isValidNumber(string(v)) {
+			return nil, fmt.Errorf("invalid number: %s", string(v))
+		}
+		return &OJsonNumber{Value: string(v)}, nil
+	case int:
+		return &OJsonNumber{Value: strconv.Itoa(v)}, nil
+	case int64:
+		return &OJsonNumber{Value: strconv.FormatInt(v, 10)}, nil
+	case uint64:
+		return &OJsonNumber{Value: strconv.FormatUint(v, 10)}, nil
+	case *big.Int:
+		if v == nil {
+			return &OJsonNull{}, nil
+		}
+		return &OJsonNumber{Value: v.String()}, nil
+	default:
+		serialized, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return ParseOrderedJSON(serialized)
+	}
+}
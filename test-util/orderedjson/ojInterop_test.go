@@ -0,0 +1,132 @@
+package orderedjson
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type decodeTestTx struct {
+	Function  string   `json:"function"`
+	Arguments []string `json:"arguments"`
+	GasLimit  uint64   `json:"gasLimit"`
+	Value     *big.Int `json:"value"`
+	Internal  string   `json:"-"`
+}
+
+type decodeTestStep struct {
+	Step    string
+	Tx      *decodeTestTx `json:"tx,omitempty"`
+	Comment interface{}   `json:"comment"`
+	Raw     OJsonObject   `json:"raw"`
+}
+
+type decodeTestAccount struct {
+	Nonce uint64 `json:"nonce"`
+}
+
+// DecodeTestCode is exported, so that it can be embedded as a pointer.
+type DecodeTestCode struct {
+	Code string `json:"code"`
+}
+
+type decodeTestCheckAccount struct {
+	decodeTestAccount
+	decodeTestTx
+	*DecodeTestCode
+	Balance string `json:"balance"`
+	Nonce   string `json:"nonce"` // hides the promoted one
+}
+
+func TestMarshalUnmarshal(t *testing.T) {
+	jobj, err := ParseOrderedJSON([]byte(`{"z": 1, "a": [true, null, "x"], "big": 123456789012345678901234567890}`))
+	require.Nil(t, err)
+
+	serialized, err := json.Marshal(struct {
+		Tree OJsonObject `json:"tree"`
+	}{Tree: jobj})
+	require.Nil(t, err)
+	require.Equal(t, `{"tree":{"z":1,"a":[true,null,"x"],"big":123456789012345678901234567890}}`, string(serialized))
+
+	var wrapper struct {
+		Tree *OJsonMap `json:"tree"`
+	}
+	require.Nil(t, json.Unmarshal(serialized, &wrapper))
+	require.True(t, Equal(jobj, wrapper.Tree))
+	require.Equal(t, "z", wrapper.Tree.OrderedKV[0].Key)
+
+	var str OJsonString
+	require.NotNil(t, json.Unmarshal([]byte(`[]`), &str))
+}
+
+func TestInterfaceConversion(t *testing.T) {
+	generic := map[string]interface{}{
+		"b": []interface{}{"x", true, nil, json.Number("1.5")},
+		"a": int64(-7),
+	}
+	jobj, err := FromInterface(generic)
+	require.Nil(t, err)
+	require.Equal(t, `{"a":-7,"b":["x",true,null,1.5]}`+"\n", JSONStringWithOptions(jobj, FormatOptions{Compact: true}))
+
+	back := ToInterface(jobj).(map[string]interface{})
+	require.Equal(t, json.Number("-7"), back["a"])
+	require.Equal(t, []interface{}{"x", true, nil, json.Number("1.5")}, back["b"])
+
+	// structs keep their field order
+	jobj, err = FromInterface(decodeTestTx{Function: "f", GasLimit: 5})
+	require.Nil(t, err)
+	require.Equal(t, `{"function":"f","arguments":null,"gasLimit":5,"value":null}`+"\n", JSONStringWithOptions(jobj, FormatOptions{Compact: true}))
+}
+
+func TestDecode(t *testing.T) {
+	jobj, err := ParseOrderedJSON([]byte(`[
+		{ "step": "scCall", "tx": { "function": "add", "arguments": ["1"], "gasLimit": 100, "value": 1e18 } },
+		{ "step": "checkState", "comment": { "x": [1] }, "raw": { "kept": true } }
+	]`))
+	require.Nil(t, err)
+
+	var steps []decodeTestStep
+	require.Nil(t, Decode(jobj, &steps))
+	require.Equal(t, 2, len(steps))
+	require.Equal(t, "scCall", steps[0].Step)
+	require.Equal(t, []string{"1"}, steps[0].Tx.Arguments)
+	require.Equal(t, uint64(100), steps[0].Tx.GasLimit)
+	require.Equal(t, "1000000000000000000", steps[0].Tx.Value.String())
+	require.Nil(t, steps[1].Tx)
+	require.Equal(t, map[string]interface{}{"x": []interface{}{json.Number("1")}}, steps[1].Comment)
+	require.Equal(t, `{"kept":true}`+"\n", JSONStringWithOptions(steps[1].Raw, FormatOptions{Compact: true}))
+
+	jobj, err = ParseOrderedJSON([]byte(`{ "step": "scCall",
+		"tx": { "function": "add", "gas": 100 } }`))
+	require.Nil(t, err)
+	var step decodeTestStep
+	err = Decode(jobj, &step)
+	require.Equal(t, "2:30: cannot decode /tx: unknown key: gas", err.Error())
+
+	jobj, err = ParseOrderedJSON([]byte(`{ "tx": { "gasLimit": -1 } }`))
+	require.Nil(t, err)
+	err = Decode(jobj, &step)
+	require.Equal(t, "1:23: cannot decode /tx/gasLimit: invalid uint64: -1", err.Error())
+
+	require.NotNil(t, Decode(jobj, step))
+}
+
+func TestDecodeEmbedded(t *testing.T) {
+	jobj, err := ParseOrderedJSON([]byte(`{"nonce": "*", "balance": "5", "function": "f", "gasLimit": 3, "code": "c"}`))
+	require.Nil(t, err)
+	var account decodeTestCheckAccount
+	require.Nil(t, Decode(jobj, &account))
+	require.Equal(t, "*", account.Nonce)
+	require.Equal(t, uint64(0), account.decodeTestAccount.Nonce)
+	require.Equal(t, "5", account.Balance)
+	require.Equal(t, "f", account.Function)
+	require.Equal(t, uint64(3), account.GasLimit)
+	require.Equal(t, "c", account.Code)
+
+	jobj, err = ParseOrderedJSON([]byte(`{"decodeTestAccount": {}}`))
+	require.Nil(t, err)
+	err = Decode(jobj, &account)
+	require.Equal(t, "1:2: cannot decode root: unknown key: decodeTestAccount", err.Error())
+}
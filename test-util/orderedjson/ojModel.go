@@ -1,37 +1,79 @@
 package orderedjson
 
 import (
+	"fmt"
+	"math/big"
 	"strings"
 )
 
 // OJsonObject is an ordered JSON tree object interface.
 type OJsonObject interface {
-	writeJSON(sb *strings.Builder, indent int)
+	// Position yields where the object starts in the parsed input.
+	// Objects created in code have an invalid (zero) position.
+	Position() Position
+
+	writeJSON(f *jsonFormatter, indent int)
 }
 
 // OJsonKeyValuePair is a key-value pair in a JSON map.
 // Since this is ordered JSON, maps are really ordered lists of key value pairs.
 type OJsonKeyValuePair struct {
-	Key   string
-	Value OJsonObject
+	Key      string
+	KeyPos   Position
+	Value    OJsonObject
+	Comments []string // comments before the key, JSONC only
 }
 
 // OJsonMap is an ordered map, actually a list of key value pairs.
 type OJsonMap struct {
 	KeySet    map[string]bool
 	OrderedKV []*OJsonKeyValuePair
+	Pos       Position
+
+	Comments        []string // comments before the map, JSONC only
+	ClosingComments []string // comments before the closing bracket, JSONC only
 }
 
 // OJsonList is a JSON list.
-type OJsonList []OJsonObject
+type OJsonList struct {
+	Items []OJsonObject
+	Pos   Position
+
+	Comments        []string // comments before the list, JSONC only
+	ClosingComments []string // comments before the closing bracket, JSONC only
+}
 
 // OJsonString is a JSON string value.
 type OJsonString struct {
 	Value string
+	Pos   Position
+
+	Comments []string // comments before the value, JSONC only
 }
 
 // OJsonBool is a JSON bool value.
-type OJsonBool bool
+type OJsonBool struct {
+	Value bool
+	Pos   Position
+
+	Comments []string // comments before the value, JSONC only
+}
+
+// OJsonNumber is a JSON number value.
+// The original literal is kept as it is, so no precision is lost for large numbers.
+type OJsonNumber struct {
+	Value string
+	Pos   Position
+
+	Comments []string // comments before the value, JSONC only
+}
+
+// OJsonNull is the JSON null value.
+type OJsonNull struct {
+	Pos Position
+
+	Comments []string // comments before the value, JSONC only
+}
 
 // NewMap is a create new ordered "map" instance.
 func NewMap() *OJsonMap {
@@ -40,13 +82,80 @@ func NewMap() *OJsonMap {
 }
 
 // Put puts into map. Does nothing if key exists in map.
+// Note that the parser does not use Put, duplicate keys in the input are handled according to ParseOptions.
 func (j *OJsonMap) Put(key string, value OJsonObject) {
+	j.put(key, Position{}, value)
+}
+
+// put yields the new key value pair, or nil if the key was already in the map.
+func (j *OJsonMap) put(key string, keyPos Position, value OJsonObject) *OJsonKeyValuePair {
 	_, alreadyInserted := j.KeySet[key]
-	if !alreadyInserted {
-		j.KeySet[key] = true
-		keyValuePair := &OJsonKeyValuePair{Key: key, Value: value}
-		j.OrderedKV = append(j.OrderedKV, keyValuePair)
+	if alreadyInserted {
+		return nil
+	}
+	j.KeySet[key] = true
+	keyValuePair := &OJsonKeyValuePair{Key: key, KeyPos: keyPos, Value: value}
+	j.OrderedKV = append(j.OrderedKV, keyValuePair)
+	return keyValuePair
+}
+
+// Get yields the value associated with a key.
+func (j *OJsonMap) Get(key string) (OJsonObject, bool) {
+	kv := j.findKV(key)
+	if kv == nil {
+		return nil, false
+	}
+	return kv.Value, true
+}
+
+// Set replaces the value of an existing key, keeping its place in the map.
+// New keys are added at the end.
+func (j *OJsonMap) Set(key string, value OJsonObject) {
+	kv := j.findKV(key)
+	if kv == nil {
+		j.put(key, Position{}, value)
+		return
+	}
+	kv.Value = value
+}
+
+// Delete removes a key from the map. Yields false if the key was not there.
+func (j *OJsonMap) Delete(key string) bool {
+	for i, kv := range j.OrderedKV {
+		if kv.Key == key {
+			j.OrderedKV = append(j.OrderedKV[:i:i], j.OrderedKV[i+1:]...)
+			delete(j.KeySet, key)
+			return true
+		}
+	}
+	return false
+}
+
+// InsertAfter adds a new key, right after an existing one.
+// Fails if the existing key is missing, or if the new key is already in the map.
+func (j *OJsonMap) InsertAfter(afterKey string, key string, value OJsonObject) error {
+	if _, alreadyInserted := j.KeySet[key]; alreadyInserted {
+		return fmt.Errorf("key already in map: %s", key)
+	}
+	for i, kv := range j.OrderedKV {
+		if kv.Key == afterKey {
+			newKV := &OJsonKeyValuePair{Key: key, Value: value}
+			j.OrderedKV = append(j.OrderedKV[:i+1:i+1], append([]*OJsonKeyValuePair{newKV}, j.OrderedKV[i+1:]...)...)
+			j.KeySet[key] = true
+			return nil
+		}
 	}
+	return fmt.Errorf("key not found: %s", afterKey)
+}
+
+// findKV yields the key value pair with the given key, nil if not found.
+func (j *OJsonMap) findKV(key string) *OJsonKeyValuePair {
+	for _, kv := range j.OrderedKV {
+		if kv.Key == key {
+			return kv
+		}
+	}
+	return nil
 }
 
 // Size yields the size of ordered map.
@@ -64,5 +173,111 @@ func (j *OJsonMap) RefreshKeySet() {
 
 // AsList converts a JSON list to a slice of objects.
 func (j *OJsonList) AsList() []OJsonObject {
-	return []OJsonObject(*j)
+	return j.Items
+}
+
+// IsInteger yields true if the number has no fractional part and no exponent.
+func (j *OJsonNumber) IsInteger() bool {
+	return !strings.ContainsAny(j.Value, ".eE")
+}
+
+// BigInt converts the number to an integer.
+// It also accepts fractions and exponents, as long as the value is a whole number, e.g. "1e18" or "2.50e1".
+// Returns false if the number is not a whole number.
+func (j *OJsonNumber) BigInt() (*big.Int, bool) {
+	if j.IsInteger() {
+		return big.NewInt(0).SetString(j.Value, 10)
+	}
+	rat, ok := big.NewRat(0, 1).SetString(j.Value)
+	if !ok || !rat.IsInt() {
+		return nil, false
+	}
+	return rat.Num(), true
+}
+
+// Position yields where the map starts in the parsed input.
+func (j *OJsonMap) Position() Position {
+	return j.Pos
+}
+
+// Position yields where the list starts in the parsed input.
+func (j *OJsonList) Position() Position {
+	return j.Pos
+}
+
+// Position yields where the string starts in the parsed input.
+func (j *OJsonString) Position() Position {
+	return j.Pos
+}
+
+// Position yields where the bool starts in the parsed input.
+func (j *OJsonBool) Position() Position {
+	return j.Pos
+}
+
+// Position yields where the number starts in the parsed input.
+func (j *OJsonNumber) Position() Position {
+	return j.Pos
+}
+
+// Position yields where the null starts in the parsed input.
+func (j *OJsonNull) Position() Position {
+	return j.Pos
+}
+
+// Clone yields a deep copy of an ordered JSON tree.
+// Positions and comments are copied too.
+func Clone(jobj OJsonObject) OJsonObject {
+	switch j := jobj.(type) {
+	case *OJsonMap:
+		result := NewMap()
+		result.Pos = j.Pos
+		result.Comments = cloneComments(j.Comments)
+		result.ClosingComments = cloneComments(j.ClosingComments)
+		for _, kv := range j.OrderedKV {
+			result.KeySet[kv.Key] = true
+			result.OrderedKV = append(result.OrderedKV, &OJsonKeyValuePair{
+				Key:      kv.Key,
+				KeyPos:   kv.KeyPos,
+				Value:    Clone(kv.Value),
+				Comments: cloneComments(kv.Comments),
+			})
+		}
+		return result
+	case *OJsonList:
+		result := &OJsonList{
+			Pos:             j.Pos,
+			Comments:        cloneComments(j.Comments),
+			ClosingComments: cloneComments(j.ClosingComments),
+		}
+		for _, item := range j.Items {
+			result.Items = append(result.Items, Clone(item))
+		}
+		return result
+	case *OJsonString:
+		result := *j
+		result.Comments = cloneComments(j.Comments)
+		return &result
+	case *OJsonBool:
+		result := *j
+		result.Comments = cloneComments(j.Comments)
+		return &result
+	case *OJsonNumber:
+		result := *j
+		result.Comments = cloneComments(j.Comments)
+		return &result
+	case *OJsonNull:
+		result := *j
+		result.Comments = cloneComments(j.Comments)
+		return &result
+	default:
+		return jobj
+	}
+}
+
+func cloneComments(comments []string) []string {
+	if comments == nil {
+		return nil
+	}
+	return append([]string{}, comments...)
 }
@@ -2,242 +2,300 @@ package orderedjson
 
 import (
 	"bytes"
-	"errors"
-	"strings"
+	"fmt"
+	"io"
 )
 
-type jsonParserState interface {
+func isWhitespace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t'
 }
 
-type jsonParserStateAnyObjPlaceholder struct {
+func describeChar(c byte) string {
+	if c >= 0x20 && c < 0x7f {
+		return fmt.Sprintf("'%c'", c)
+	}
+	return fmt.Sprintf("0x%02x", c)
 }
 
-type jsonParserStateSingleValue struct {
-	buffer       bytes.Buffer
-	stringEscape bool
+func errorAtf(pos Position, format string, args ...interface{}) error {
+	return ErrorAt(pos, fmt.Errorf(format, args...))
 }
 
-type jsonParserStateMap struct {
-	currentMap *OJsonMap
+// ParseOrderedJSON parses JSON preserving order in maps.
+// All resulting objects are annotated with their position in the input.
+// Errors are of type *PositionError, pointing to the offending character.
+func ParseOrderedJSON(input []byte) (OJsonObject, error) {
+	return ParseOrderedJSONWithOptions(input, ParseOptions{})
 }
 
-type jsonStateMapKeyValue struct {
-	keyBuffer bytes.Buffer
-	state     int // 0=key, 1=':', 2=value
-	currentKV OJsonKeyValuePair
+// ParseOrderedJSONWithOptions parses JSON preserving order in maps, like ParseOrderedJSON.
+// The options select the dialect, how duplicate keys are handled and the limits for untrusted input.
+func ParseOrderedJSONWithOptions(input []byte, options ParseOptions) (OJsonObject, error) {
+	return ParseOrderedJSONReader(bytes.NewReader(input), options)
 }
 
-type jsonParserStateList struct {
-	list OJsonList
+// ParseOrderedJSONReader parses JSON from a stream, preserving order in maps.
+// The input is tokenized as it is read, it never needs to be entirely in memory.
+// Errors from the reader are returned as they are, all other errors are of type *PositionError.
+func ParseOrderedJSONReader(reader io.Reader, options ParseOptions) (OJsonObject, error) {
+	p := &parser{
+		tokenizer: newTokenizer(reader, options),
+		options:   options,
+	}
+	first, err := p.tokenizer.next()
+	if err != nil {
+		return nil, err
+	}
+	result, err := p.parseValue(first)
+	if err != nil {
+		return nil, err
+	}
+
+	// only whitespace and comments can follow
+	last, err := p.tokenizer.next()
+	if err != nil {
+		return nil, err
+	}
+	if last.kind != tokenEOF {
+		return nil, errorAtf(last.pos, "unexpected character %s after the end of the JSON value", describeChar(last.firstChar()))
+	}
+	return result, nil
 }
 
-func isWhitespace(c byte) bool {
-	return c == ' ' || c == '\n' || c == '\r' || c == '\t'
+// parser builds the JSON tree by recursive descent over the tokens.
+type parser struct {
+	tokenizer *tokenizer
+	options   ParseOptions
+	depth     int // number of maps and lists currently open
 }
 
-// ParseOrderedJSON parses JSON preserving order in maps
-func ParseOrderedJSON(input []byte) (OJsonObject, error) {
-	stateStack := &jsonParserStateStack{}
-	stateStack.push(&jsonParserStateAnyObjPlaceholder{})
-	var pendingResult OJsonObject
-
-	for i, c := range input {
-		done := false
-		for !done {
-			done = true
-
-			if stateStack.size() == 0 {
-				if isWhitespace(c) {
-					continue
-				} else {
-					return nil, errors.New("unexpected characters at the end")
-				}
-			}
-
-			state := stateStack.peek()
-			switch specificState := state.(type) {
-			case *jsonParserStateAnyObjPlaceholder:
-				if pendingResult != nil {
-					return nil, errors.New("invalid state")
-				}
-				if isWhitespace(c) {
-					// leading whitespace, ignore
-				} else if c == '{' {
-					// replace with map state
-					stateStack.replaceTop(&jsonParserStateMap{currentMap: NewMap()})
-				} else if c == '[' {
-					// replace with list state
-					stateStack.replaceTop(&jsonParserStateList{})
-				} else if c == ']' || c == '}' || c == ',' {
-					return nil, errors.New("misplaced character")
-				} else {
-					// replace with single value
-					stateStack.replaceTop(&jsonParserStateSingleValue{})
-					done = false
-				}
-			case *jsonParserStateSingleValue:
-				if specificState.buffer.Len() == 0 {
-					specificState.stringEscape = (c == '"')
-					specificState.buffer.WriteByte(c)
-				} else {
-					prevChar := input[i-1]
-					if specificState.stringEscape {
-						specificState.buffer.WriteByte(c)
-						if c == '"' && prevChar != '\\' {
-							stateStack.pop()
-							var err error
-							pendingResult, err = specificState.finalize()
-							if err != nil {
-								return nil, err
-							}
-						}
-					} else {
-						if c == ']' || c == '}' || c == ',' || isWhitespace(c) {
-							stateStack.pop()
-							var err error
-							pendingResult, err = specificState.finalize()
-							if err != nil {
-								return nil, err
-							}
-							done = false
-						} else {
-							specificState.buffer.WriteByte(c)
-						}
-					}
-				}
-			case *jsonParserStateList:
-				if pendingResult != nil {
-					specificState.list = append(specificState.list, pendingResult)
-					pendingResult = nil
-				}
-				if isWhitespace(c) {
-					// ignore
-				} else {
-					if c == ']' {
-						pendingResult = &specificState.list
-						stateStack.pop()
-					} else if len(specificState.list) == 0 {
-						// new empty list
-						stateStack.push(&jsonParserStateAnyObjPlaceholder{})
-						done = false
-					} else if c == ',' {
-						stateStack.push(&jsonParserStateAnyObjPlaceholder{})
-					}
-				}
-			case *jsonParserStateMap:
-				if isWhitespace(c) {
-					// ignore
-				} else if c == '}' {
-					pendingResult = specificState.currentMap
-					stateStack.pop()
-				} else if c == ',' {
-					stateStack.push(&jsonStateMapKeyValue{})
-				} else if specificState.currentMap.Size() == 0 {
-					stateStack.push(&jsonStateMapKeyValue{})
-					done = false
-				} else {
-					return nil, errors.New("invalid map state")
-				}
-			case *jsonStateMapKeyValue:
-				switch specificState.state {
-				case 0: // key
-					if specificState.keyBuffer.Len() == 0 {
-						if isWhitespace(c) {
-							// ignore
-						} else {
-							if c != '"' {
-								return nil, errors.New("map key must start with a quote")
-							}
-							specificState.keyBuffer.WriteByte(c)
-						}
-					} else {
-						specificState.keyBuffer.WriteByte(c)
-						prevChar := input[i-1]
-						if c == '"' && prevChar != '\\' {
-							specificState.state = 1
-						}
-					}
-				case 1: // ':'
-					if isWhitespace(c) {
-						// ignore
-					} else if c == ':' {
-						specificState.state = 2
-						stateStack.push(&jsonParserStateAnyObjPlaceholder{})
-					} else {
-						return nil, errors.New("invalid character in map definition, colon expected")
-					}
-				case 2: // value
-					if pendingResult == nil {
-						return nil, errors.New("missing value in map")
-					}
-					key := specificState.keyBuffer.String()
-					if !strings.HasPrefix(key, "\"") || !strings.HasSuffix(key, "\"") {
-						return nil, errors.New("map key should be a string enclosed in quotes")
-					}
-					key = key[1 : len(key)-1]
-					stateStack.pop()
-					mapState, isMap := stateStack.peek().(*jsonParserStateMap)
-					if !isMap {
-						return nil, errors.New("map key value state, but no map state underneath")
-					}
-					mapState.currentMap.Put(key, pendingResult)
-					pendingResult = nil
-					done = false
-				default:
-					return nil, errors.New("unknown jsonStateMapKeyValue state")
-				}
-			default:
-				return nil, errors.New("invalid parser state")
-			}
-		}
-	}
-
-	if stateStack.size() != 0 {
-		return nil, errors.New("state stack should be empty at the end")
-	}
-
-	return pendingResult, nil
-}
-
-func (s *jsonParserStateSingleValue) finalize() (OJsonObject, error) {
-	str := s.buffer.String()
-	if strings.HasPrefix(str, "\"") && strings.HasSuffix(str, "\"") {
-		str = str[1 : len(str)-1]
-		return &OJsonString{Value: str}, nil
+// enter is called when a map or list starts, to check the depth limit.
+// Every successful call must be followed by a call to leave.
+func (p *parser) enter(pos Position) error {
+	if maxDepth := p.options.maxDepth(); p.depth >= maxDepth {
+		return ErrorAt(pos, &LimitError{Limit: LimitDepth, Max: maxDepth})
 	}
-	if str == "true" {
-		result := OJsonBool(true)
-		return &result, nil
-	}
-	if str == "false" {
-		result := OJsonBool(false)
-		return &result, nil
+	p.depth++
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) allowTrailingCommas() bool {
+	return p.options.Dialect == DialectJSONC
+}
+
+// parseValue parses the value starting with the given token.
+func (p *parser) parseValue(first token) (OJsonObject, error) {
+	switch first.kind {
+	case tokenBeginMap, tokenBeginList:
+		if err := p.enter(first.pos); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		if first.kind == tokenBeginMap {
+			return p.parseMap(first)
+		}
+		return p.parseList(first)
+	case tokenString:
+		value, err := decodeString(first.text, first.pos)
+		if err != nil {
+			return nil, err
+		}
+		return &OJsonString{Value: value, Pos: first.pos, Comments: p.tokenizer.takeComments()}, nil
+	case tokenLiteral:
+		return parseLiteral(first.text, first.pos, p.tokenizer.takeComments())
+	case tokenEOF:
+		return nil, p.tokenizer.unexpectedEOF()
+	default:
+		return nil, errorAtf(first.pos, "unexpected character %s, value expected", describeChar(first.firstChar()))
 	}
-	return nil, errors.New("Invalid value: " + str)
 }
 
-type jsonParserStateStack struct {
-	stack []jsonParserState
+func (p *parser) parseMap(first token) (OJsonObject, error) {
+	result := NewMap()
+	result.Pos = first.pos
+	result.Comments = p.tokenizer.takeComments()
+
+	for {
+		tok, err := p.tokenizer.next()
+		if err != nil {
+			return nil, err
+		}
+		if tok.kind == tokenEndMap && (result.Size() == 0 || p.allowTrailingCommas()) {
+			result.ClosingComments = p.tokenizer.takeComments()
+			return result, nil
+		}
+		if tok.kind == tokenEOF {
+			return nil, p.tokenizer.unexpectedEOF()
+		}
+		if tok.kind != tokenString {
+			return nil, errorAtf(tok.pos, "unexpected character %s, map key must start with a quote", describeChar(tok.firstChar()))
+		}
+		keyComments := p.tokenizer.takeComments()
+		key, err := decodeString(tok.text, tok.pos)
+		if err != nil {
+			return nil, err
+		}
+		keyPos := tok.pos
+
+		tok, err = p.tokenizer.next()
+		if err != nil {
+			return nil, err
+		}
+		if tok.kind == tokenEOF {
+			return nil, p.tokenizer.unexpectedEOF()
+		}
+		if tok.kind != tokenColon {
+			return nil, errorAtf(tok.pos, "unexpected character %s in map, ':' expected", describeChar(tok.firstChar()))
+		}
+
+		tok, err = p.tokenizer.next()
+		if err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue(tok)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.putInMap(result, key, keyPos, value, keyComments); err != nil {
+			return nil, err
+		}
+
+		tok, err = p.tokenizer.next()
+		if err != nil {
+			return nil, err
+		}
+		switch tok.kind {
+		case tokenComma:
+		case tokenEndMap:
+			result.ClosingComments = p.tokenizer.takeComments()
+			return result, nil
+		case tokenEOF:
+			return nil, p.tokenizer.unexpectedEOF()
+		default:
+			return nil, errorAtf(tok.pos, "unexpected character %s in map, ',' or '}' expected", describeChar(tok.firstChar()))
+		}
+	}
 }
 
-func (s *jsonParserStateStack) push(state jsonParserState) {
-	s.stack = append(s.stack, state)
+// putInMap adds a key to a map that is being parsed, resolving duplicates according to the options.
+func (p *parser) putInMap(result *OJsonMap, key string, keyPos Position, value OJsonObject, keyComments []string) error {
+	if kv := result.put(key, keyPos, value); kv != nil {
+		kv.Comments = keyComments
+		if p.options.MaxKeyCount > 0 && result.Size() > p.options.MaxKeyCount {
+			return ErrorAt(keyPos, &LimitError{Limit: LimitKeyCount, Max: p.options.MaxKeyCount})
+		}
+		return nil
+	}
+
+	existing := result.findKV(key)
+	switch p.options.DuplicateKeys {
+	case DuplicateKeysFirstWins:
+	case DuplicateKeysLastWins:
+		existing.Value = value
+	default:
+		return ErrorAt(keyPos, &DuplicateKeyError{Key: key, FirstPos: existing.KeyPos})
+	}
+	return nil
 }
 
-func (s *jsonParserStateStack) replaceTop(state jsonParserState) {
-	s.stack[len(s.stack)-1] = state
+func (p *parser) parseList(first token) (OJsonObject, error) {
+	result := &OJsonList{Pos: first.pos, Comments: p.tokenizer.takeComments()}
+
+	for {
+		tok, err := p.tokenizer.next()
+		if err != nil {
+			return nil, err
+		}
+		if tok.kind == tokenEndList && (len(result.Items) == 0 || p.allowTrailingCommas()) {
+			result.ClosingComments = p.tokenizer.takeComments()
+			return result, nil
+		}
+		item, err := p.parseValue(tok)
+		if err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, item)
+
+		tok, err = p.tokenizer.next()
+		if err != nil {
+			return nil, err
+		}
+		switch tok.kind {
+		case tokenComma:
+		case tokenEndList:
+			result.ClosingComments = p.tokenizer.takeComments()
+			return result, nil
+		case tokenEOF:
+			return nil, p.tokenizer.unexpectedEOF()
+		default:
+			return nil, errorAtf(tok.pos, "unexpected character %s in list, ',' or ']' expected", describeChar(tok.firstChar()))
+		}
+	}
 }
 
-func (s *jsonParserStateStack) peek() jsonParserState {
-	return s.stack[len(s.stack)-1]
+// parseLiteral converts a number, boolean or null literal.
+func parseLiteral(str string, pos Position, comments []string) (OJsonObject, error) {
+	if str == "true" {
+		return &OJsonBool{Value: true, Pos: pos, Comments: comments}, nil
+	}
+	if str == "false" {
+		return &OJsonBool{Value: false, Pos: pos, Comments: comments}, nil
+	}
+	if str == "null" {
+		return &OJsonNull{Pos: pos, Comments: comments}, nil
+	}
+	if isValidNumber(str) {
+		return &OJsonNumber{Value: str, Pos: pos, Comments: comments}, nil
+	}
+	return nil, errorAtf(pos, "invalid value: %s", str)
 }
 
-func (s *jsonParserStateStack) pop() jsonParserState {
-	top := s.stack[len(s.stack)-1]
-	s.stack = s.stack[0 : len(s.stack)-1]
-	return top
+// decodeString resolves escape sequences in the contents of a string literal that starts at the given position.
+func decodeString(raw string, literalPos Position) (string, error) {
+	value, errOffset, err := decodeStringContents(raw)
+	if err != nil {
+		return "", ErrorAt(literalPos.advance("\""+raw[:errOffset]), err)
+	}
+	return value, nil
 }
 
-func (s *jsonParserStateStack) size() int {
-	return len(s.stack)
+// isValidNumber checks the JSON number grammar: -?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?
+func isValidNumber(str string) bool {
+	i := 0
+	digits := func() int {
+		start := i
+		for i < len(str) && str[i] >= '0' && str[i] <= '9' {
+			i++
+		}
+		return i - start
+	}
+
+	if i < len(str) && str[i] == '-' {
+		i++
+	}
+	if i < len(str) && str[i] == '0' {
+		i++
+	} else if digits() == 0 {
+		return false
+	}
+	if i < len(str) && str[i] == '.' {
+		i++
+		if digits() == 0 {
+			return false
+		}
+	}
+	if i < len(str) && (str[i] == 'e' || str[i] == 'E') {
+		i++
+		if i < len(str) && (str[i] == '+' || str[i] == '-') {
+			i++
+		}
+		if digits() == 0 {
+			return false
+		}
+	}
+	return i == len(str)
 }
@@ -0,0 +1,123 @@
+package orderedjson
+
+import (
+	"fmt"
+)
+
+// Dialect selects the flavor of JSON accepted by the parser.
+type Dialect int
+
+const (
+	// DialectJSON is plain JSON, as in RFC 8259.
+	DialectJSON Dialect = iota
+
+	// DialectJSONC is JSON with comments.
+	// It allows "//" line comments, "/* */" block comments and trailing commas in maps and lists.
+	// Comments are attached to the node that follows them, so they can be written back.
+	// Comments before a closing bracket go to the ClosingComments of the map or list.
+	// Comments after the end of the root object are ignored.
+	DialectJSONC
+)
+
+// DuplicateKeys selects what happens when a map contains the same key more than once.
+type DuplicateKeys int
+
+const (
+	// DuplicateKeysError rejects the input, with a *DuplicateKeyError.
+	DuplicateKeysError DuplicateKeys = iota
+
+	// DuplicateKeysFirstWins keeps the first value, later ones are ignored.
+	DuplicateKeysFirstWins
+
+	// DuplicateKeysLastWins keeps the last value, but in the place of the first occurrence of the key.
+	DuplicateKeysLastWins
+)
+
+// DefaultMaxDepth is the nesting limit when ParseOptions.MaxDepth is 0.
+// Maps and lists are parsed recursively, so there is always a limit, to keep deep input from exhausting the stack.
+const DefaultMaxDepth = 1000
+
+// ParseOptions configures ParseOrderedJSONWithOptions.
+// The zero value parses plain JSON, rejects duplicate keys and has no limits, except DefaultMaxDepth.
+type ParseOptions struct {
+	Dialect       Dialect
+	DuplicateKeys DuplicateKeys
+
+	// Limits protect against untrusted input, 0 means unlimited, except for MaxDepth.
+	// Exceeding any of them yields a *LimitError, wrapped in a *PositionError.
+
+	// MaxDepth is the maximum nesting of maps and lists. A root map or list has depth 1.
+	// 0 means DefaultMaxDepth.
+	MaxDepth int
+
+	// MaxDocumentSize is the maximum size of the input, in bytes.
+	MaxDocumentSize int
+
+	// MaxStringLength is the maximum length in bytes of any single string, key, literal or comment, as written in the input.
+	MaxStringLength int
+
+	// MaxKeyCount is the maximum number of keys in any single map.
+	MaxKeyCount int
+}
+
+// maxDepth yields MaxDepth, or DefaultMaxDepth if it is not set.
+func (options ParseOptions) maxDepth() int {
+	if options.MaxDepth <= 0 {
+		return DefaultMaxDepth
+	}
+	return options.MaxDepth
+}
+
+// DuplicateKeyError signals that a key appears twice in the same map.
+// The parser wraps it in a *PositionError, pointing to the second occurrence.
+type DuplicateKeyError struct {
+	Key      string
+	FirstPos Position
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("duplicate key %q, first defined at %s", e.Key, e.FirstPos.String())
+}
+
+// Limit identifies one of the limits in ParseOptions.
+type Limit int
+
+const (
+	// LimitDepth is ParseOptions.MaxDepth.
+	LimitDepth Limit = iota
+
+	// LimitDocumentSize is ParseOptions.MaxDocumentSize.
+	LimitDocumentSize
+
+	// LimitStringLength is ParseOptions.MaxStringLength.
+	LimitStringLength
+
+	// LimitKeyCount is ParseOptions.MaxKeyCount.
+	LimitKeyCount
+)
+
+func (l Limit) String() string {
+	switch l {
+	case LimitDepth:
+		return "nesting depth"
+	case LimitDocumentSize:
+		return "document size"
+	case LimitStringLength:
+		return "string length"
+	case LimitKeyCount:
+		return "key count"
+	default:
+		return "unknown limit"
+	}
+}
+
+// LimitError signals that the input exceeds one of the limits in ParseOptions.
+// The parser wraps it in a *PositionError, pointing to where the limit was hit.
+type LimitError struct {
+	Limit Limit
+	Max   int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("maximum %s of %d exceeded", e.Limit.String(), e.Max)
+}
@@ -0,0 +1,480 @@
+package orderedjson
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+)
+
+type jsonParserState interface {
+}
+
+type jsonParserStateAnyObjPlaceholder struct {
+}
+
+type jsonParserStateSingleValue struct {
+	buffer       bytes.Buffer
+	stringEscape bool
+	escaped      bool
+	startPos     Position
+}
+
+type jsonParserStateMap struct {
+	currentMap *OJsonMap
+}
+
+type jsonStateMapKeyValue struct {
+	keyBuffer bytes.Buffer
+	keyPos    Position
+	escaped   bool
+	state     int // 0=key, 1=':', 2=value
+	currentKV OJsonKeyValuePair
+}
+
+type jsonParserStateList struct {
+	list *OJsonList
+}
+
+// parseOrderedJSONStateMachine is the original, byte-by-byte state machine parser.
+// It is kept in the tests as a reference for the tokenizer based parser.
+// Comments are stripped in a separate pass and attached to the tree afterwards.
+func parseOrderedJSONStateMachine(input []byte, options ParseOptions) (OJsonObject, error) {
+	if options.Dialect != DialectJSONC {
+		return parseOrderedJSONStateMachineDialect(input, false)
+	}
+
+	stripped, comments, err := stripComments(input)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseOrderedJSONStateMachineDialect(stripped, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(comments) > 0 {
+		attacher := &commentAttacher{src: stripped, comments: comments}
+		attacher.attach(result)
+	}
+	return result, nil
+}
+
+func parseOrderedJSONStateMachineDialect(input []byte, allowTrailingCommas bool) (OJsonObject, error) {
+	stateStack := &jsonParserStateStack{}
+	stateStack.push(&jsonParserStateAnyObjPlaceholder{})
+	var pendingResult OJsonObject
+	pos := Position{Line: 1, Column: 1, Offset: 0}
+
+	for i, c := range input {
+		pos.Offset = i
+		if i > 0 {
+			if input[i-1] == '\n' {
+				pos.Line++
+				pos.Column = 1
+			} else {
+				pos.Column++
+			}
+		}
+
+		done := false
+		for !done {
+			done = true
+
+			if stateStack.size() == 0 {
+				if isWhitespace(c) {
+					continue
+				} else {
+					return nil, errorAtf(pos, "unexpected character %s after the end of the JSON value", describeChar(c))
+				}
+			}
+
+			state := stateStack.peek()
+			switch specificState := state.(type) {
+			case *jsonParserStateAnyObjPlaceholder:
+				if pendingResult != nil {
+					return nil, errorAtf(pos, "invalid parser state")
+				}
+				if isWhitespace(c) {
+					// leading whitespace, ignore
+				} else if c == '{' {
+					// replace with map state
+					newMap := NewMap()
+					newMap.Pos = pos
+					stateStack.replaceTop(&jsonParserStateMap{currentMap: newMap})
+				} else if c == '[' {
+					// replace with list state
+					stateStack.replaceTop(&jsonParserStateList{list: &OJsonList{Pos: pos}})
+				} else if c == ']' && allowTrailingCommas && isNonEmptyListState(stateStack.peekParent()) {
+					// trailing comma, let the list handle the closing bracket
+					stateStack.pop()
+					done = false
+				} else if c == ']' || c == '}' || c == ',' || c == ':' {
+					return nil, errorAtf(pos, "unexpected character %s, value expected", describeChar(c))
+				} else {
+					// replace with single value
+					stateStack.replaceTop(&jsonParserStateSingleValue{startPos: pos})
+					done = false
+				}
+			case *jsonParserStateSingleValue:
+				if specificState.buffer.Len() == 0 {
+					specificState.stringEscape = (c == '"')
+					specificState.buffer.WriteByte(c)
+				} else {
+					if specificState.stringEscape {
+						specificState.buffer.WriteByte(c)
+						if specificState.escaped {
+							specificState.escaped = false
+						} else if c == '\\' {
+							specificState.escaped = true
+						} else if c == '"' {
+							stateStack.pop()
+							var err error
+							pendingResult, err = specificState.finalize()
+							if err != nil {
+								return nil, err
+							}
+						}
+					} else {
+						if c == ']' || c == '}' || c == ',' || isWhitespace(c) {
+							stateStack.pop()
+							var err error
+							pendingResult, err = specificState.finalize()
+							if err != nil {
+								return nil, err
+							}
+							done = false
+						} else {
+							specificState.buffer.WriteByte(c)
+						}
+					}
+				}
+			case *jsonParserStateList:
+				if pendingResult != nil {
+					specificState.list.Items = append(specificState.list.Items, pendingResult)
+					pendingResult = nil
+				}
+				if isWhitespace(c) {
+					// ignore
+				} else {
+					if c == ']' {
+						pendingResult = specificState.list
+						stateStack.pop()
+					} else if len(specificState.list.Items) == 0 {
+						// new empty list
+						stateStack.push(&jsonParserStateAnyObjPlaceholder{})
+						done = false
+					} else if c == ',' {
+						stateStack.push(&jsonParserStateAnyObjPlaceholder{})
+					} else {
+						return nil, errorAtf(pos, "unexpected character %s in list, ',' or ']' expected", describeChar(c))
+					}
+				}
+			case *jsonParserStateMap:
+				if isWhitespace(c) {
+					// ignore
+				} else if c == '}' {
+					pendingResult = specificState.currentMap
+					stateStack.pop()
+				} else if c == ',' && specificState.currentMap.Size() > 0 {
+					stateStack.push(&jsonStateMapKeyValue{})
+				} else if specificState.currentMap.Size() == 0 {
+					stateStack.push(&jsonStateMapKeyValue{})
+					done = false
+				} else {
+					return nil, errorAtf(pos, "unexpected character %s in map, ',' or '}' expected", describeChar(c))
+				}
+			case *jsonStateMapKeyValue:
+				switch specificState.state {
+				case 0: // key
+					if specificState.keyBuffer.Len() == 0 {
+						if isWhitespace(c) {
+							// ignore
+						} else if c == '}' && allowTrailingCommas && isNonEmptyMapState(stateStack.peekParent()) {
+							// trailing comma, let the map handle the closing bracket
+							stateStack.pop()
+							done = false
+						} else {
+							if c != '"' {
+								return nil, errorAtf(pos, "unexpected character %s, map key must start with a quote", describeChar(c))
+							}
+							specificState.keyPos = pos
+							specificState.keyBuffer.WriteByte(c)
+						}
+					} else {
+						specificState.keyBuffer.WriteByte(c)
+						if specificState.escaped {
+							specificState.escaped = false
+						} else if c == '\\' {
+							specificState.escaped = true
+						} else if c == '"' {
+							specificState.state = 1
+						}
+					}
+				case 1: // ':'
+					if isWhitespace(c) {
+						// ignore
+					} else if c == ':' {
+						specificState.state = 2
+						stateStack.push(&jsonParserStateAnyObjPlaceholder{})
+					} else {
+						return nil, errorAtf(pos, "unexpected character %s in map, ':' expected", describeChar(c))
+					}
+				case 2: // value
+					if pendingResult == nil {
+						return nil, errorAtf(pos, "missing value in map")
+					}
+					key := specificState.keyBuffer.String()
+					if !strings.HasPrefix(key, "\"") || !strings.HasSuffix(key, "\"") {
+						return nil, errorAtf(specificState.keyPos, "map key should be a string enclosed in quotes")
+					}
+					key, err := decodeString(key[1:len(key)-1], specificState.keyPos)
+					if err != nil {
+						return nil, err
+					}
+					stateStack.pop()
+					mapState, isMap := stateStack.peek().(*jsonParserStateMap)
+					if !isMap {
+						return nil, errorAtf(pos, "map key value state, but no map state underneath")
+					}
+					mapState.currentMap.put(key, specificState.keyPos, pendingResult)
+					pendingResult = nil
+					done = false
+				default:
+					return nil, errorAtf(pos, "unknown jsonStateMapKeyValue state")
+				}
+			default:
+				return nil, errorAtf(pos, "invalid parser state")
+			}
+		}
+	}
+
+	// a number or literal at the very end of the input has no delimiter to end it
+	if stateStack.size() == 1 {
+		if singleValueState, isSingleValue := stateStack.peek().(*jsonParserStateSingleValue); isSingleValue &&
+			!singleValueState.stringEscape {
+			stateStack.pop()
+			var err error
+			pendingResult, err = singleValueState.finalize()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if stateStack.size() != 0 {
+		endPos := Position{Line: pos.Line, Column: pos.Column + 1, Offset: len(input)}
+		if len(input) == 0 {
+			endPos = Position{Line: 1, Column: 1, Offset: 0}
+		} else if input[len(input)-1] == '\n' {
+			endPos = Position{Line: pos.Line + 1, Column: 1, Offset: len(input)}
+		}
+		return nil, errorAtf(endPos, "unexpected end of input")
+	}
+
+	return pendingResult, nil
+}
+
+func (s *jsonParserStateSingleValue) finalize() (OJsonObject, error) {
+	str := s.buffer.String()
+	if s.stringEscape {
+		value, err := decodeString(str[1:len(str)-1], s.startPos)
+		if err != nil {
+			return nil, err
+		}
+		return &OJsonString{Value: value, Pos: s.startPos}, nil
+	}
+	if str == "true" {
+		return &OJsonBool{Value: true, Pos: s.startPos}, nil
+	}
+	if str == "false" {
+		return &OJsonBool{Value: false, Pos: s.startPos}, nil
+	}
+	if str == "null" {
+		return &OJsonNull{Pos: s.startPos}, nil
+	}
+	if isValidNumber(str) {
+		return &OJsonNumber{Value: str, Pos: s.startPos}, nil
+	}
+	return nil, errorAtf(s.startPos, "invalid value: %s", str)
+}
+
+func isNonEmptyListState(state jsonParserState) bool {
+	listState, isList := state.(*jsonParserStateList)
+	return isList && len(listState.list.Items) > 0
+}
+
+func isNonEmptyMapState(state jsonParserState) bool {
+	mapState, isMap := state.(*jsonParserStateMap)
+	return isMap && mapState.currentMap.Size() > 0
+}
+
+type jsonParserStateStack struct {
+	stack []jsonParserState
+}
+
+func (s *jsonParserStateStack) push(state jsonParserState) {
+	s.stack = append(s.stack, state)
+}
+
+func (s *jsonParserStateStack) replaceTop(state jsonParserState) {
+	s.stack[len(s.stack)-1] = state
+}
+
+func (s *jsonParserStateStack) peek() jsonParserState {
+	return s.stack[len(s.stack)-1]
+}
+
+// peekParent yields the state under the top of the stack, nil if there is none.
+func (s *jsonParserStateStack) peekParent() jsonParserState {
+	if len(s.stack) < 2 {
+		return nil
+	}
+	return s.stack[len(s.stack)-2]
+}
+
+func (s *jsonParserStateStack) pop() jsonParserState {
+	top := s.stack[len(s.stack)-1]
+	s.stack = s.stack[0 : len(s.stack)-1]
+	return top
+}
+
+func (s *jsonParserStateStack) size() int {
+	return len(s.stack)
+}
+
+// jsonComment is a comment found in JSONC input.
+type jsonComment struct {
+	text  string
+	start int
+}
+
+// stripComments replaces all comments in the input with whitespace and collects them.
+// Newlines are kept and the input length does not change, so positions in the result
+// are the same as in the original input.
+func stripComments(input []byte) ([]byte, []jsonComment, error) {
+	var stripped []byte
+	var comments []jsonComment
+	inString := false
+	escaped := false
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		if inString {
+			if escaped {
+				escaped = false
+			} else if c == '\\' {
+				escaped = true
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		if c == '"' {
+			inString = true
+			continue
+		}
+		if c != '/' || i+1 >= len(input) || (input[i+1] != '/' && input[i+1] != '*') {
+			continue
+		}
+
+		var end int
+		if input[i+1] == '/' {
+			end = i + 2
+			for end < len(input) && input[end] != '\n' && input[end] != '\r' {
+				end++
+			}
+		} else {
+			closing := strings.Index(string(input[i+2:]), "*/")
+			if closing == -1 {
+				return nil, nil, ErrorAt(positionOfOffset(input, i), errors.New("unterminated comment"))
+			}
+			end = i + 2 + closing + 2
+		}
+
+		if stripped == nil {
+			stripped = make([]byte, len(input))
+			copy(stripped, input)
+		}
+		comments = append(comments, jsonComment{text: string(input[i:end]), start: i})
+		for j := i; j < end; j++ {
+			if !isWhitespace(input[j]) {
+				stripped[j] = ' '
+			}
+		}
+		i = end - 1
+	}
+	if stripped == nil {
+		return input, nil, nil
+	}
+	return stripped, comments, nil
+}
+
+// positionOfOffset yields the line and column of a byte offset in the input.
+func positionOfOffset(input []byte, offset int) Position {
+	return Position{Line: 1, Column: 1}.advance(string(input[:offset]))
+}
+
+// commentAttacher distributes comments to the nodes that follow them.
+type commentAttacher struct {
+	src      []byte
+	comments []jsonComment
+	next     int
+}
+
+// take yields all remaining comments that start before the given offset.
+func (ca *commentAttacher) take(offset int) []string {
+	var taken []string
+	for ca.next < len(ca.comments) && ca.comments[ca.next].start < offset {
+		taken = append(taken, ca.comments[ca.next].text)
+		ca.next++
+	}
+	return taken
+}
+
+// attach assigns comments to a node and its descendants, in document order.
+// Works on the input with the comments stripped. Yields the offset right after the node.
+func (ca *commentAttacher) attach(jobj OJsonObject) int {
+	start := jobj.Position().Offset
+	switch j := jobj.(type) {
+	case *OJsonMap:
+		j.Comments = ca.take(start)
+		cursor := start + 1
+		for _, kv := range j.OrderedKV {
+			kv.Comments = ca.take(kv.KeyPos.Offset)
+			cursor = ca.attach(kv.Value)
+		}
+		closePos := findClosingBracket(ca.src, cursor, '}')
+		j.ClosingComments = ca.take(closePos)
+		return closePos + 1
+	case *OJsonList:
+		j.Comments = ca.take(start)
+		cursor := start + 1
+		for _, item := range j.Items {
+			cursor = ca.attach(item)
+		}
+		closePos := findClosingBracket(ca.src, cursor, ']')
+		j.ClosingComments = ca.take(closePos)
+		return closePos + 1
+	case *OJsonString:
+		j.Comments = ca.take(start)
+		return scanStringEnd(ca.src, start)
+	case *OJsonNumber:
+		j.Comments = ca.take(start)
+		return scanLiteralEnd(ca.src, start)
+	case *OJsonBool:
+		j.Comments = ca.take(start)
+		return scanLiteralEnd(ca.src, start)
+	case *OJsonNull:
+		j.Comments = ca.take(start)
+		return scanLiteralEnd(ca.src, start)
+	default:
+		return start
+	}
+}
+
+// findClosingBracket skips whitespace and trailing commas, until the closing bracket.
+func findClosingBracket(src []byte, start int, bracket byte) int {
+	i := start
+	for i < len(src) && src[i] != bracket {
+		i++
+	}
+	return i
+}
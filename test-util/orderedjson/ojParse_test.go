@@ -0,0 +1,277 @@
+package orderedjson
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// randomJSONWriter generates random, mostly valid, JSON documents.
+type randomJSONWriter struct {
+	rnd   *rand.Rand
+	sb    strings.Builder
+	jsonc bool
+}
+
+var randomStrings = []string{
+	"", "a", "key", "0x1234", "''abc", `\"`, `\\`, `\/\b\f\n\r\t`, `\u00e9`, `\ud83d\ude00`, `\ud83d`, "ö", "😃", "//", "/*",
+}
+
+var randomNumbers = []string{
+	"0", "-0", "1", "-1", "123", "1.5", "-0.25", "1e18", "2E+3", "1.5e-3", "123456789012345678901234567890",
+}
+
+var randomLiterals = []string{"true", "false", "null"}
+
+func (w *randomJSONWriter) trivia() {
+	switch w.rnd.Intn(6) {
+	case 0:
+		w.sb.WriteString(" ")
+	case 1:
+		w.sb.WriteString("\n  ")
+	case 2:
+		w.sb.WriteString("\t\r\n")
+	case 3:
+		if w.jsonc {
+			w.sb.WriteString(" // line comment\n")
+		}
+	case 4:
+		if w.jsonc {
+			w.sb.WriteString("/* block */")
+		}
+	}
+}
+
+func (w *randomJSONWriter) value(depth int) {
+	w.trivia()
+	kind := w.rnd.Intn(5)
+	if depth <= 0 && kind < 2 {
+		kind += 2
+	}
+	switch kind {
+	case 0:
+		w.sb.WriteString("{")
+		n := w.rnd.Intn(4)
+		keys := w.rnd.Perm(len(randomStrings)) // no duplicate keys
+		for i := 0; i < n; i++ {
+			w.trivia()
+			w.sb.WriteString(`"` + randomStrings[keys[i]] + `"`)
+			w.trivia()
+			w.sb.WriteString(":")
+			w.value(depth - 1)
+			if i < n-1 || (w.jsonc && w.rnd.Intn(3) == 0) {
+				w.sb.WriteString(",")
+			}
+		}
+		w.trivia()
+		w.sb.WriteString("}")
+	case 1:
+		w.sb.WriteString("[")
+		n := w.rnd.Intn(4)
+		for i := 0; i < n; i++ {
+			w.value(depth - 1)
+			if i < n-1 || (w.jsonc && w.rnd.Intn(3) == 0) {
+				w.sb.WriteString(",")
+			}
+		}
+		w.trivia()
+		w.sb.WriteString("]")
+	case 2:
+		w.sb.WriteString(`"` + randomStrings[w.rnd.Intn(len(randomStrings))] + `"`)
+	case 3:
+		w.sb.WriteString(randomNumbers[w.rnd.Intn(len(randomNumbers))])
+	default:
+		w.sb.WriteString(randomLiterals[w.rnd.Intn(len(randomLiterals))])
+	}
+	w.trivia()
+}
+
+// mutate randomly corrupts the input, to also check that both parsers reject the same inputs.
+func mutate(rnd *rand.Rand, input []byte) []byte {
+	const interesting = "{}[],:\"\\/* \nx0-.e"
+	mutated := append([]byte{}, input...)
+	if len(mutated) == 0 {
+		return mutated
+	}
+	i := rnd.Intn(len(mutated))
+	c := interesting[rnd.Intn(len(interesting))]
+	switch rnd.Intn(3) {
+	case 0:
+		mutated[i] = c
+	case 1:
+		mutated = append(mutated[:i], mutated[i+1:]...)
+	default:
+		mutated = append(mutated[:i], append([]byte{c}, mutated[i:]...)...)
+	}
+	return mutated
+}
+
+func requireParsersAgree(t *testing.T, input []byte, options ParseOptions) {
+	expected, expectedErr := parseOrderedJSONStateMachine(input, options)
+	options.DuplicateKeys = DuplicateKeysFirstWins // like the state machine parser
+	actual, actualErr := ParseOrderedJSONWithOptions(input, options)
+	if expectedErr != nil {
+		require.NotNil(t, actualErr, "input accepted by the tokenizer parser only: %q", input)
+		return
+	}
+	require.Nil(t, actualErr, "input accepted by the state machine parser only: %q", input)
+	require.Equal(t, expected, actual, "different trees for input: %q", input)
+}
+
+func TestParsersAgree(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 3000; i++ {
+		options := ParseOptions{}
+		if i%2 == 1 {
+			options.Dialect = DialectJSONC
+		}
+		w := &randomJSONWriter{rnd: rnd, jsonc: options.Dialect == DialectJSONC}
+		w.value(4)
+		input := []byte(w.sb.String())
+
+		requireParsersAgree(t, input, options)
+		for j := 0; j < 5; j++ {
+			requireParsersAgree(t, mutate(rnd, input), options)
+		}
+	}
+}
+
+func TestParseReader(t *testing.T) {
+	input := "{\n  \"a\": [1, 2.5, \"x\"], // comment\n  \"b\": null\n}"
+	options := ParseOptions{Dialect: DialectJSONC}
+	expected, err := ParseOrderedJSONWithOptions([]byte(input), options)
+	require.Nil(t, err)
+
+	actual, err := ParseOrderedJSONReader(iotest.OneByteReader(strings.NewReader(input)), options)
+	require.Nil(t, err)
+	require.Equal(t, expected, actual)
+
+	readErr := errors.New("read failed")
+	_, err = ParseOrderedJSONReader(iotest.TimeoutReader(iotest.OneByteReader(strings.NewReader(input))), options)
+	require.Equal(t, iotest.ErrTimeout, err)
+	_, err = ParseOrderedJSONReader(&failingReader{err: readErr}, options)
+	require.Equal(t, readErr, err)
+}
+
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
+
+func TestDuplicateKeys(t *testing.T) {
+	input := []byte("{\n  \"a\": 1,\n  \"b\": 2,\n  \"a\": 3\n}")
+
+	_, err := ParseOrderedJSON(input)
+	require.Equal(t, "4:3: duplicate key \"a\", first defined at 2:3", err.Error())
+	var dupErr *DuplicateKeyError
+	require.True(t, errors.As(err, &dupErr))
+	require.Equal(t, "a", dupErr.Key)
+
+	jobj, err := ParseOrderedJSONWithOptions(input, ParseOptions{DuplicateKeys: DuplicateKeysFirstWins})
+	require.Nil(t, err)
+	require.Equal(t, "{\n    \"a\": 1,\n    \"b\": 2\n}\n", JSONString(jobj))
+
+	jobj, err = ParseOrderedJSONWithOptions(input, ParseOptions{DuplicateKeys: DuplicateKeysLastWins})
+	require.Nil(t, err)
+	require.Equal(t, "{\n    \"a\": 3,\n    \"b\": 2\n}\n", JSONString(jobj))
+
+	// keys are compared after decoding
+	_, err = ParseOrderedJSON([]byte(`{"\u0061": 1, "a": 2}`))
+	require.NotNil(t, err)
+}
+
+func requireLimitError(t *testing.T, err error, limit Limit, expectedMessage string) {
+	require.NotNil(t, err)
+	require.Equal(t, expectedMessage, err.Error())
+	var limitErr *LimitError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, limit, limitErr.Limit)
+}
+
+func TestParseLimits(t *testing.T) {
+	input := []byte("{\n  \"a\": [[1, 2]],\n  \"b\": \"abcdef\",\n  \"c\": 3\n}")
+
+	_, err := ParseOrderedJSONWithOptions(input, ParseOptions{MaxDepth: 3, MaxDocumentSize: len(input), MaxStringLength: 6, MaxKeyCount: 3})
+	require.Nil(t, err)
+
+	_, err = ParseOrderedJSONWithOptions(input, ParseOptions{MaxDepth: 2})
+	requireLimitError(t, err, LimitDepth, "2:9: maximum nesting depth of 2 exceeded")
+
+	_, err = ParseOrderedJSONWithOptions(input, ParseOptions{MaxDocumentSize: 20})
+	requireLimitError(t, err, LimitDocumentSize, "3:2: maximum document size of 20 exceeded")
+
+	_, err = ParseOrderedJSONWithOptions(input, ParseOptions{MaxStringLength: 5})
+	requireLimitError(t, err, LimitStringLength, "3:8: maximum string length of 5 exceeded")
+
+	_, err = ParseOrderedJSONWithOptions(input, ParseOptions{MaxKeyCount: 2})
+	requireLimitError(t, err, LimitKeyCount, "4:3: maximum key count of 2 exceeded")
+
+	// comments count as strings
+	_, err = ParseOrderedJSONWithOptions([]byte("// a long comment\n1"), ParseOptions{Dialect: DialectJSONC, MaxStringLength: 5})
+	requireLimitError(t, err, LimitStringLength, "1:1: maximum string length of 5 exceeded")
+
+	// very deep input does not exhaust the stack
+	deep := strings.Repeat("[", 1000000)
+	_, err = ParseOrderedJSONWithOptions([]byte(deep), ParseOptions{MaxDepth: 100})
+	requireLimitError(t, err, LimitDepth, "1:101: maximum nesting depth of 100 exceeded")
+
+	// even without options
+	_, err = ParseOrderedJSON([]byte(strings.Repeat("[", 2000000)))
+	requireLimitError(t, err, LimitDepth, "1:1001: maximum nesting depth of 1000 exceeded")
+	_, err = ParseOrderedJSON([]byte(strings.Repeat("[", DefaultMaxDepth) + strings.Repeat("]", DefaultMaxDepth)))
+	require.Nil(t, err)
+}
+
+func benchmarkInput() []byte {
+	var sb strings.Builder
+	sb.WriteString("{\n    \"steps\": [")
+	for i := 0; i < 500; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(`
+        {
+            "step": "scCall",
+            "txId": "tx-1",
+            "tx": {
+                "from": "''sender_address_______________s1",
+                "to": "0x1000000000000000000000000000000000000000000000000000000000000000",
+                "value": 123456789,
+                "arguments": ["0x01", "''message", 1.5e3, true, null],
+                "gasLimit": "0x100000"
+            }
+        }`)
+	}
+	sb.WriteString("\n    ]\n}\n")
+	return []byte(sb.String())
+}
+
+func BenchmarkParseTokenizer(b *testing.B) {
+	input := benchmarkInput()
+	b.SetBytes(int64(len(input)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseOrderedJSONReader(bytes.NewReader(input), ParseOptions{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseStateMachine(b *testing.B) {
+	input := benchmarkInput()
+	b.SetBytes(int64(len(input)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := parseOrderedJSONStateMachine(input, ParseOptions{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package orderedjson
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MergePatch applies a JSON Merge Patch (RFC 7386) and yields the result.
+// Keys already in the target keep their place, new keys are added at the end of their map.
+// The target is not modified, the result is a new tree.
+func MergePatch(target OJsonObject, patch OJsonObject) OJsonObject {
+	if target != nil {
+		target = Clone(target)
+	}
+	return mergePatch(target, patch)
+}
+
+func mergePatch(target OJsonObject, patch OJsonObject) OJsonObject {
+	patchMap, isPatchMap := patch.(*OJsonMap)
+	if !isPatchMap {
+		return Clone(patch)
+	}
+	targetMap, isTargetMap := target.(*OJsonMap)
+	if !isTargetMap {
+		targetMap = NewMap()
+	}
+	for _, kv := range patchMap.OrderedKV {
+		if _, isNull := kv.Value.(*OJsonNull); isNull {
+			targetMap.Delete(kv.Key)
+			continue
+		}
+		existing, _ := targetMap.Get(kv.Key)
+		targetMap.Set(kv.Key, mergePatch(existing, kv.Value))
+	}
+	return targetMap
+}
+
+// ApplyPatch applies a JSON Patch (RFC 6902) document, i.e. a list of operations, and yields the result.
+// Replaced keys keep their place, added keys go to the end of their map.
+// The target is not modified, the result is a new tree.
+// If any operation fails, the whole patch fails.
+func ApplyPatch(target OJsonObject, patch OJsonObject) (OJsonObject, error) {
+	operations, isList := patch.(*OJsonList)
+	if !isList {
+		return nil, errors.New("JSON patch must be a list of operations")
+	}
+	result := Clone(target)
+	for i, operation := range operations.Items {
+		var err error
+		result, err = applyPatchOperation(result, operation)
+		if err != nil {
+			return nil, fmt.Errorf("JSON patch operation %d: %w", i, err)
+		}
+	}
+	return result, nil
+}
+
+func applyPatchOperation(doc OJsonObject, operation OJsonObject) (OJsonObject, error) {
+	opMap, isMap := operation.(*OJsonMap)
+	if !isMap {
+		return nil, errors.New("operation must be a map")
+	}
+	opName, err := patchStringField(opMap, "op")
+	if err != nil {
+		return nil, err
+	}
+	pathStr, err := patchStringField(opMap, "path")
+	if err != nil {
+		return nil, err
+	}
+	path, err := ParsePointer(pathStr)
+	if err != nil {
+		return nil, err
+	}
+
+	switch opName {
+	case "add", "replace", "test":
+		value, hasValue := opMap.Get("value")
+		if !hasValue {
+			return nil, fmt.Errorf("%s operation without value", opName)
+		}
+		switch opName {
+		case "add":
+			return patchAdd(doc, path, Clone(value))
+		case "replace":
+			return patchReplace(doc, path, Clone(value))
+		default:
+			existing, err := path.Resolve(doc)
+			if err != nil {
+				return nil, err
+			}
+			if !Equal(existing, value) {
+				return nil, fmt.Errorf("test failed, unexpected value at %s", path.String())
+			}
+			return doc, nil
+		}
+	case "remove":
+		doc, _, err = patchRemove(doc, path)
+		return doc, err
+	case "move", "copy":
+		fromStr, err := patchStringField(opMap, "from")
+		if err != nil {
+			return nil, err
+		}
+		from, err := ParsePointer(fromStr)
+		if err != nil {
+			return nil, err
+		}
+		if opName == "copy" {
+			value, err := from.Resolve(doc)
+			if err != nil {
+				return nil, err
+			}
+			return patchAdd(doc, path, Clone(value))
+		}
+		if len(path) > len(from) && path[:len(from)].String() == from.String() {
+			return nil, fmt.Errorf("cannot move %s into itself", from.String())
+		}
+		doc, value, err := patchRemove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, path, value)
+	default:
+		return nil, fmt.Errorf("unknown operation: %s", opName)
+	}
+}
+
+func patchStringField(opMap *OJsonMap, key string) (string, error) {
+	value, found := opMap.Get(key)
+	if !found {
+		return "", fmt.Errorf("missing field: %s", key)
+	}
+	str, isStr := value.(*OJsonString)
+	if !isStr {
+		return "", fmt.Errorf("field %s must be a string", key)
+	}
+	return str.Value, nil
+}
+
+// patchParent resolves the container of the object referenced by the path.
+func patchParent(doc OJsonObject, path Pointer) (OJsonObject, string, error) {
+	parent, err := path.Parent().Resolve(doc)
+	if err != nil {
+		return nil, "", err
+	}
+	return parent, path[len(path)-1], nil
+}
+
+func patchAdd(doc OJsonObject, path Pointer, value OJsonObject) (OJsonObject, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, token, err := patchParent(doc, path)
+	if err != nil {
+		return nil, err
+	}
+	switch j := parent.(type) {
+	case *OJsonMap:
+		j.Set(token, value)
+	case *OJsonList:
+		index := len(j.Items)
+		if token != "-" {
+			index, err = parseListIndex(token, len(j.Items)+1)
+			if err != nil {
+				return nil, err
+			}
+		}
+		j.Items = append(j.Items[:index:index], append([]OJsonObject{value}, j.Items[index:]...)...)
+	default:
+		return nil, fmt.Errorf("cannot add to %s, parent is not a map or list", path.String())
+	}
+	return doc, nil
+}
+
+func patchReplace(doc OJsonObject, path Pointer, value OJsonObject) (OJsonObject, error) {
+	if _, err := path.Resolve(doc); err != nil {
+		return nil, err
+	}
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, token, err := patchParent(doc, path)
+	if err != nil {
+		return nil, err
+	}
+	switch j := parent.(type) {
+	case *OJsonMap:
+		j.Set(token, value)
+	case *OJsonList:
+		index, _ := parseListIndex(token, len(j.Items))
+		j.Items[index] = value
+	}
+	return doc, nil
+}
+
+// patchRemove yields the document without the referenced object, and the removed object.
+func patchRemove(doc OJsonObject, path Pointer) (OJsonObject, OJsonObject, error) {
+	removed, err := path.Resolve(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(path) == 0 {
+		return nil, removed, errors.New("cannot remove the root")
+	}
+	parent, token, err := patchParent(doc, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch j := parent.(type) {
+	case *OJsonMap:
+		j.Delete(token)
+	case *OJsonList:
+		index, _ := parseListIndex(token, len(j.Items))
+		j.Items = append(j.Items[:index:index], j.Items[index+1:]...)
+	}
+	return doc, removed, nil
+}
@@ -0,0 +1,117 @@
+package orderedjson
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pointer is a JSON Pointer (RFC 6901), as a list of unescaped reference tokens.
+// The empty pointer refers to the whole document.
+type Pointer []string
+
+// ParsePointer parses a JSON Pointer, e.g. "/steps/0/tx/function".
+func ParsePointer(str string) (Pointer, error) {
+	if str == "" {
+		return Pointer{}, nil
+	}
+	if str[0] != '/' {
+		return nil, fmt.Errorf("JSON pointer must start with '/': %s", str)
+	}
+	tokens := strings.Split(str[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 >= len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, fmt.Errorf("invalid escape sequence in JSON pointer: %s", str)
+			}
+		}
+		tokens[i] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+	}
+	return Pointer(tokens), nil
+}
+
+// String yields the pointer in its escaped form.
+func (p Pointer) String() string {
+	var sb strings.Builder
+	for _, token := range p {
+		sb.WriteString("/")
+		sb.WriteString(strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1))
+	}
+	return sb.String()
+}
+
+// Append yields a new pointer, one level deeper.
+func (p Pointer) Append(token string) Pointer {
+	result := make(Pointer, len(p), len(p)+1)
+	copy(result, p)
+	return append(result, token)
+}
+
+// AppendIndex yields a new pointer to a list item.
+func (p Pointer) AppendIndex(index int) Pointer {
+	return p.Append(strconv.Itoa(index))
+}
+
+// Parent yields the pointer to the container of the referenced object.
+// The parent of the empty pointer is the empty pointer.
+func (p Pointer) Parent() Pointer {
+	if len(p) == 0 {
+		return p
+	}
+	return p[:len(p)-1]
+}
+
+// Resolve yields the object referenced by the pointer.
+func (p Pointer) Resolve(root OJsonObject) (OJsonObject, error) {
+	current := root
+	for i, token := range p {
+		child, err := resolveChild(current, token)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve %s: %w", p[:i+1].String(), err)
+		}
+		current = child
+	}
+	return current, nil
+}
+
+// ResolvePointer yields the object referenced by a JSON Pointer string.
+func ResolvePointer(root OJsonObject, pointer string) (OJsonObject, error) {
+	p, err := ParsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	return p.Resolve(root)
+}
+
+func resolveChild(parent OJsonObject, token string) (OJsonObject, error) {
+	switch j := parent.(type) {
+	case *OJsonMap:
+		value, found := j.Get(token)
+		if !found {
+			return nil, fmt.Errorf("key not found: %s", token)
+		}
+		return value, nil
+	case *OJsonList:
+		index, err := parseListIndex(token, len(j.Items))
+		if err != nil {
+			return nil, err
+		}
+		return j.Items[index], nil
+	default:
+		return nil, errors.New("not a map or list")
+	}
+}
+
+// parseListIndex converts a reference token to a list index.
+// As per RFC 6901, leading zeros are not allowed.
+func parseListIndex(token string, length int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid list index: %s", token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index >= length {
+		return 0, fmt.Errorf("list index out of range: %s", token)
+	}
+	return index, nil
+}
@@ -0,0 +1,62 @@
+package orderedjson
+
+import (
+	"fmt"
+)
+
+// Position identifies a place in the JSON input.
+// Lines and columns start from 1, columns are counted in bytes.
+// The byte offset starts from 0.
+// The zero value means that the position is unknown.
+type Position struct {
+	Line   int
+	Column int
+	Offset int
+}
+
+// IsValid yields true if the position was set by the parser.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String yields the position in the "line:column" format.
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// advance yields the position right after the given text, if the text starts at p.
+func (p Position) advance(text string) Position {
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			p.Line++
+			p.Column = 1
+		} else {
+			p.Column++
+		}
+	}
+	p.Offset += len(text)
+	return p
+}
+
+// PositionError is an error that can be traced back to a position in the JSON input.
+type PositionError struct {
+	Pos Position
+	Err error
+}
+
+// ErrorAt associates an error with a position in the JSON input.
+func ErrorAt(pos Position, err error) *PositionError {
+	return &PositionError{Pos: pos, Err: err}
+}
+
+func (e *PositionError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos.String(), e.Err.Error())
+}
+
+// Unwrap yields the underlying error.
+func (e *PositionError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,201 @@
+package orderedjson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type querySelectorKind int
+
+const (
+	selectKey querySelectorKind = iota
+	selectIndex
+	selectAll
+)
+
+type querySelector struct {
+	kind  querySelectorKind
+	key   string
+	index int // negative indexes count from the end of the list
+}
+
+// Query is a compiled path query over ordered JSON trees.
+//
+// The syntax is close to the one used in JavaScript:
+//
+//	steps[*].tx.function - the function of all transactions in a scenario
+//	steps[0].accounts.*  - all accounts in the first step
+//	["key.with.dots"][-1] - the last item of a list, under a key that needs quoting
+//
+// A "*" or "[*]" selects all values of a map or all items of a list.
+// Selecting a key in a list, or an index in a map, simply yields no result.
+type Query struct {
+	source    string
+	selectors []querySelector
+}
+
+// QueryMatch is an object found by a query, together with its location.
+type QueryMatch struct {
+	Path  Pointer
+	Value OJsonObject
+}
+
+// CompileQuery parses a path query. The empty query selects the root.
+func CompileQuery(path string) (*Query, error) {
+	q := &Query{source: path}
+	i := 0
+	expectKey := true // a key can start the query, or follow a dot
+	for i < len(path) {
+		switch {
+		case path[i] == '[':
+			end := -1 // offset of the closing bracket
+			if i+1 < len(path) && path[i+1] == '"' {
+				end = scanQueryStringEnd(path, i+1)
+			} else if relativeEnd := strings.IndexByte(path[i:], ']'); relativeEnd != -1 {
+				end = i + relativeEnd
+			}
+			if end == -1 || end >= len(path) || path[end] != ']' {
+				return nil, fmt.Errorf("unterminated '[' at %d in query: %s", i, path)
+			}
+			selector, err := parseBracketSelector(path[i+1 : end])
+			if err != nil {
+				return nil, fmt.Errorf("%w, in query: %s", err, path)
+			}
+			q.selectors = append(q.selectors, selector)
+			i = end + 1
+			expectKey = false
+		case path[i] == '.' && !expectKey:
+			i++
+			expectKey = true
+			if i >= len(path) {
+				return nil, fmt.Errorf("query cannot end with '.': %s", path)
+			}
+		case expectKey:
+			end := i
+			for end < len(path) && path[end] != '.' && path[end] != '[' && path[end] != ']' {
+				end++
+			}
+			if end == i {
+				return nil, fmt.Errorf("empty key at %d in query: %s", i, path)
+			}
+			key := path[i:end]
+			if key == "*" {
+				q.selectors = append(q.selectors, querySelector{kind: selectAll})
+			} else {
+				q.selectors = append(q.selectors, querySelector{kind: selectKey, key: key})
+			}
+			i = end
+			expectKey = false
+		default:
+			return nil, fmt.Errorf("unexpected character %s at %d in query: %s", describeChar(path[i]), i, path)
+		}
+	}
+	return q, nil
+}
+
+// scanQueryStringEnd yields the offset right after a quoted key, -1 if unterminated.
+func scanQueryStringEnd(path string, start int) int {
+	escaped := false
+	for i := start + 1; i < len(path); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case path[i] == '\\':
+			escaped = true
+		case path[i] == '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+func parseBracketSelector(contents string) (querySelector, error) {
+	if contents == "*" {
+		return querySelector{kind: selectAll}, nil
+	}
+	if strings.HasPrefix(contents, "\"") {
+		if len(contents) < 2 || !strings.HasSuffix(contents, "\"") {
+			return querySelector{}, fmt.Errorf("invalid quoted key: %s", contents)
+		}
+		key, _, err := decodeStringContents(contents[1 : len(contents)-1])
+		if err != nil {
+			return querySelector{}, fmt.Errorf("invalid quoted key: %s", contents)
+		}
+		return querySelector{kind: selectKey, key: key}, nil
+	}
+	index, err := strconv.Atoi(contents)
+	if err != nil {
+		return querySelector{}, fmt.Errorf("invalid list index: %s", contents)
+	}
+	return querySelector{kind: selectIndex, index: index}, nil
+}
+
+// String yields the query, as it was compiled.
+func (q *Query) String() string {
+	return q.source
+}
+
+// FindMatches yields all objects selected by the query, in document order.
+func (q *Query) FindMatches(root OJsonObject) []QueryMatch {
+	matches := []QueryMatch{{Path: Pointer{}, Value: root}}
+	for _, selector := range q.selectors {
+		var next []QueryMatch
+		for _, match := range matches {
+			next = selector.apply(match, next)
+		}
+		matches = next
+	}
+	return matches
+}
+
+// Find yields all objects selected by the query, in document order.
+func (q *Query) Find(root OJsonObject) []OJsonObject {
+	matches := q.FindMatches(root)
+	result := make([]OJsonObject, len(matches))
+	for i, match := range matches {
+		result[i] = match.Value
+	}
+	return result
+}
+
+// Find is a shorthand for compiling a query and running it once.
+func Find(root OJsonObject, path string) ([]OJsonObject, error) {
+	q, err := CompileQuery(path)
+	if err != nil {
+		return nil, err
+	}
+	return q.Find(root), nil
+}
+
+func (s querySelector) apply(match QueryMatch, result []QueryMatch) []QueryMatch {
+	switch j := match.Value.(type) {
+	case *OJsonMap:
+		switch s.kind {
+		case selectKey:
+			if value, found := j.Get(s.key); found {
+				result = append(result, QueryMatch{Path: match.Path.Append(s.key), Value: value})
+			}
+		case selectAll:
+			for _, kv := range j.OrderedKV {
+				result = append(result, QueryMatch{Path: match.Path.Append(kv.Key), Value: kv.Value})
+			}
+		}
+	case *OJsonList:
+		switch s.kind {
+		case selectIndex:
+			index := s.index
+			if index < 0 {
+				index += len(j.Items)
+			}
+			if index >= 0 && index < len(j.Items) {
+				result = append(result, QueryMatch{Path: match.Path.AppendIndex(index), Value: j.Items[index]})
+			}
+		case selectAll:
+			for i, item := range j.Items {
+				result = append(result, QueryMatch{Path: match.Path.AppendIndex(i), Value: item})
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,153 @@
+package orderedjson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const queryExample = `{
+	"name": "example",
+	"steps": [
+		{ "step": "scCall", "tx": { "function": "add", "arguments": ["1", "2"] } },
+		{ "step": "checkState" },
+		{ "step": "scCall", "tx": { "function": "sub" } }
+	],
+	"a/b~c": { "x.y": true }
+}`
+
+func parseQueryExample(t *testing.T) OJsonObject {
+	jobj, err := ParseOrderedJSON([]byte(queryExample))
+	require.Nil(t, err)
+	return jobj
+}
+
+func stringValues(objects []OJsonObject) []string {
+	var result []string
+	for _, obj := range objects {
+		result = append(result, obj.(*OJsonString).Value)
+	}
+	return result
+}
+
+func TestPointer(t *testing.T) {
+	root := parseQueryExample(t)
+
+	obj, err := ResolvePointer(root, "/steps/2/tx/function")
+	require.Nil(t, err)
+	require.Equal(t, "sub", obj.(*OJsonString).Value)
+
+	obj, err = ResolvePointer(root, "/a~1b~0c/x.y")
+	require.Nil(t, err)
+	require.Equal(t, true, obj.(*OJsonBool).Value)
+
+	obj, err = ResolvePointer(root, "")
+	require.Nil(t, err)
+	require.Equal(t, root, obj)
+
+	_, err = ResolvePointer(root, "/steps/01")
+	require.Equal(t, "cannot resolve /steps/01: invalid list index: 01", err.Error())
+	_, err = ResolvePointer(root, "/steps/3")
+	require.Equal(t, "cannot resolve /steps/3: list index out of range: 3", err.Error())
+	_, err = ResolvePointer(root, "/steps/1/tx")
+	require.Equal(t, "cannot resolve /steps/1/tx: key not found: tx", err.Error())
+	_, err = ResolvePointer(root, "steps")
+	require.NotNil(t, err)
+	_, err = ResolvePointer(root, "/a~2b")
+	require.NotNil(t, err)
+
+	p, err := ParsePointer("/a~1b~0c/x.y")
+	require.Nil(t, err)
+	require.Equal(t, Pointer{"a/b~c", "x.y"}, p)
+	require.Equal(t, "/a~1b~0c/x.y", p.String())
+}
+
+func TestQuery(t *testing.T) {
+	root := parseQueryExample(t)
+
+	found, err := Find(root, "steps[*].tx.function")
+	require.Nil(t, err)
+	require.Equal(t, []string{"add", "sub"}, stringValues(found))
+
+	found, err = Find(root, "steps[0].tx.arguments[-1]")
+	require.Nil(t, err)
+	require.Equal(t, []string{"2"}, stringValues(found))
+
+	found, err = Find(root, "steps.*.step")
+	require.Nil(t, err)
+	require.Equal(t, []string{"scCall", "checkState", "scCall"}, stringValues(found))
+
+	found, err = Find(root, `["a/b~c"]["x.y"]`)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(found))
+
+	found, err = Find(root, "name[0]")
+	require.Nil(t, err)
+	require.Equal(t, 0, len(found))
+
+	q, err := CompileQuery("steps[*].tx")
+	require.Nil(t, err)
+	matches := q.FindMatches(root)
+	require.Equal(t, 2, len(matches))
+	require.Equal(t, "/steps/2/tx", matches[1].Path.String())
+
+	for _, invalid := range []string{"steps[", "steps.", "steps..tx", "steps[x]", `["unterminated]`, "steps]"} {
+		_, err = CompileQuery(invalid)
+		require.NotNil(t, err, invalid)
+	}
+}
+
+func TestMapEditing(t *testing.T) {
+	m := NewMap()
+	m.Put("a", &OJsonString{Value: "1"})
+	m.Put("c", &OJsonString{Value: "3"})
+
+	require.Nil(t, m.InsertAfter("a", "b", &OJsonString{Value: "2"}))
+	require.NotNil(t, m.InsertAfter("a", "b", &OJsonString{Value: "2"}))
+	require.NotNil(t, m.InsertAfter("x", "y", &OJsonString{Value: "2"}))
+
+	m.Set("a", &OJsonString{Value: "one"})
+	m.Set("d", &OJsonString{Value: "4"})
+	require.True(t, m.Delete("c"))
+	require.False(t, m.Delete("c"))
+
+	value, found := m.Get("a")
+	require.True(t, found)
+	require.Equal(t, "one", value.(*OJsonString).Value)
+	_, found = m.Get("c")
+	require.False(t, found)
+
+	require.Equal(t, "{\n    \"a\": \"one\",\n    \"b\": \"2\",\n    \"d\": \"4\"\n}\n", JSONString(m))
+	require.Equal(t, map[string]bool{"a": true, "b": true, "d": true}, m.KeySet)
+}
+
+func TestWalkAndTransform(t *testing.T) {
+	root := parseQueryExample(t)
+
+	var visited []string
+	Walk(root, func(path Pointer, jobj OJsonObject) bool {
+		visited = append(visited, path.String())
+		return len(path) < 2
+	})
+	require.Equal(t, []string{"", "/name", "/steps", "/steps/0", "/steps/1", "/steps/2", "/a~1b~0c", "/a~1b~0c/x.y"}, visited)
+
+	// rename a function and drop all checkState steps
+	root, err := Transform(root, func(path Pointer, jobj OJsonObject) (OJsonObject, error) {
+		if str, isStr := jobj.(*OJsonString); isStr && str.Value == "add" {
+			return &OJsonString{Value: "plus"}, nil
+		}
+		if m, isMap := jobj.(*OJsonMap); isMap {
+			if step, _ := m.Get("step"); step != nil && step.(*OJsonString).Value == "checkState" {
+				return nil, nil
+			}
+		}
+		return jobj, nil
+	})
+	require.Nil(t, err)
+	found, err := Find(root, "steps[*].tx.function")
+	require.Nil(t, err)
+	require.Equal(t, []string{"plus", "sub"}, stringValues(found))
+	found, err = Find(root, "steps[*]")
+	require.Nil(t, err)
+	require.Equal(t, 2, len(found))
+}
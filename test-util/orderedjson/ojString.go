@@ -0,0 +1,136 @@
+package orderedjson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const hexDigits = "0123456789abcdef"
+
+// decodeStringContents resolves the escape sequences of a JSON string literal.
+// The input should not contain the enclosing quotes.
+// Control characters need to be escaped, as RFC 8259 requires.
+// On failure it also yields the offset of the offending escape sequence or character.
+func decodeStringContents(raw string) (string, int, error) {
+	if !needsDecoding(raw) {
+		// the usual case, nothing to decode
+		return raw, 0, nil
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(raw))
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		if c < 0x20 {
+			return "", i, fmt.Errorf("unescaped control character 0x%02x in string", c)
+		}
+		if c != '\\' {
+			sb.WriteByte(c)
+			continue
+		}
+		if i+1 >= len(raw) {
+			return "", i, fmt.Errorf("unterminated escape sequence")
+		}
+		escapeStart := i
+		i++
+		switch raw[i] {
+		case '"', '\\', '/':
+			sb.WriteByte(raw[i])
+		case 'b':
+			sb.WriteByte('\b')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case 'u':
+			r, ok := decodeHex4(raw, i+1)
+			if !ok {
+				return "", escapeStart, fmt.Errorf("invalid unicode escape sequence")
+			}
+			i += 4
+			if utf16.IsSurrogate(r) {
+				// try to complete the surrogate pair, lone surrogates become U+FFFD
+				if i+2 < len(raw) && raw[i+1] == '\\' && raw[i+2] == 'u' {
+					r2, ok2 := decodeHex4(raw, i+3)
+					if ok2 {
+						if combined := utf16.DecodeRune(r, r2); combined != utf8.RuneError {
+							r = combined
+							i += 6
+						}
+					}
+				}
+				if utf16.IsSurrogate(r) {
+					r = utf8.RuneError
+				}
+			}
+			sb.WriteRune(r)
+		default:
+			return "", escapeStart, fmt.Errorf("invalid escape sequence \\%c", raw[i])
+		}
+	}
+	return sb.String(), 0, nil
+}
+
+// needsDecoding says whether the string has escape sequences, or control characters to reject.
+func needsDecoding(raw string) bool {
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '\\' || raw[i] < 0x20 {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeHex4(raw string, start int) (rune, bool) {
+	if start+4 > len(raw) {
+		return 0, false
+	}
+	value, err := strconv.ParseUint(raw[start:start+4], 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return rune(value), true
+}
+
+// writeJSONString writes a string literal, including quotes, escaping where necessary.
+// Non-ASCII characters are written as they are.
+func writeJSONString(sb *strings.Builder, value string) {
+	sb.WriteByte('"')
+	start := 0
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c >= 0x20 && c != '"' && c != '\\' {
+			continue
+		}
+		sb.WriteString(value[start:i])
+		switch c {
+		case '"', '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case '\b':
+			sb.WriteString("\\b")
+		case '\f':
+			sb.WriteString("\\f")
+		case '\n':
+			sb.WriteString("\\n")
+		case '\r':
+			sb.WriteString("\\r")
+		case '\t':
+			sb.WriteString("\\t")
+		default:
+			sb.WriteString("\\u00")
+			sb.WriteByte(hexDigits[c>>4])
+			sb.WriteByte(hexDigits[c&0xf])
+		}
+		start = i + 1
+	}
+	sb.WriteString(value[start:])
+	sb.WriteByte('"')
+}
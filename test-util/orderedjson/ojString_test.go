@@ -0,0 +1,63 @@
+package orderedjson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringEscapes(t *testing.T) {
+	jobj, err := ParseOrderedJSON([]byte(`["a\\", "\"q\"", "\/\b\f\n\r\t", "éA", "😀", "\ud83d"]`))
+	require.Nil(t, err)
+	items := jobj.(*OJsonList).AsList()
+	require.Equal(t, "a\\", items[0].(*OJsonString).Value)
+	require.Equal(t, "\"q\"", items[1].(*OJsonString).Value)
+	require.Equal(t, "/\b\f\n\r\t", items[2].(*OJsonString).Value)
+	require.Equal(t, "éA", items[3].(*OJsonString).Value)
+	require.Equal(t, "😀", items[4].(*OJsonString).Value)
+	require.Equal(t, "�", items[5].(*OJsonString).Value)
+
+	jobj, err = ParseOrderedJSON([]byte(`{"k\"ey\\": "v"}`))
+	require.Nil(t, err)
+	require.Equal(t, "k\"ey\\", jobj.(*OJsonMap).OrderedKV[0].Key)
+
+	_, err = ParseOrderedJSON([]byte("[\n  \"ab\\x\"]"))
+	require.NotNil(t, err)
+	require.Equal(t, "2:6: invalid escape sequence \\x", err.Error())
+
+	_, err = ParseOrderedJSON([]byte(`["\u12"]`))
+	require.NotNil(t, err)
+
+	_, err = ParseOrderedJSON([]byte("[\"a\tb\"]"))
+	require.NotNil(t, err)
+	require.Equal(t, "1:4: unescaped control character 0x09 in string", err.Error())
+
+	_, err = ParseOrderedJSON([]byte("{\n  \"k\": \"line\nbreak\"}"))
+	require.NotNil(t, err)
+	require.Equal(t, "2:13: unescaped control character 0x0a in string", err.Error())
+}
+
+func TestStringRoundTrip(t *testing.T) {
+	values := []string{
+		"",
+		"plain",
+		"quote\" backslash\\ slash/",
+		"\x00\x01\x1f\x7f control",
+		"new\nline\ttab\r\b\f",
+		"unicode é 😀  ",
+		"\\u0041 not an escape",
+		string([]byte{0xff, 0xfe, 'x'}),
+	}
+	for _, value := range values {
+		m := NewMap()
+		m.Put(value, &OJsonString{Value: value})
+		serialized := JSONString(m)
+
+		parsed, err := ParseOrderedJSON([]byte(serialized))
+		require.Nil(t, err, serialized)
+		kv := parsed.(*OJsonMap).OrderedKV[0]
+		require.Equal(t, value, kv.Key)
+		require.Equal(t, value, kv.Value.(*OJsonString).Value)
+		require.Equal(t, serialized, JSONString(parsed))
+	}
+}
@@ -1,72 +1,329 @@
 package orderedjson
 
 import (
-	"fmt"
+	"io"
+	"sort"
 	"strings"
 )
 
-// JSONString returns a formatted string representation of an ordered JSON
-func JSONString(j OJsonObject) string {
-	var sb strings.Builder
-	j.writeJSON(&sb, 0)
-	sb.WriteString("\n")
-	return sb.String()
+// FormatOptions configures how JSON is written.
+// The zero value gives the default style of JSONString:
+// 4 spaces of indentation and one element per line.
+type FormatOptions struct {
+	// IndentWidth is the number of spaces per level, 4 if not set.
+	IndentWidth int
+
+	// UseTabs indents with one tab per level, instead of spaces.
+	UseTabs bool
+
+	// MaxLineWidth, if set, allows lists and maps to be written on a single line,
+	// as long as the line does not get longer than this.
+	MaxLineWidth int
+
+	// Compact writes everything on a single line, without any whitespace and without comments.
+	Compact bool
+
+	// SortKeys writes map keys in sorted order, rather than in the original order.
+	// Together with Compact, it yields a canonical form.
+	SortKeys bool
+}
+
+// flushThreshold is how much output is buffered before being passed on to the io.Writer.
+const flushThreshold = 1 << 16
+
+// jsonFormatter accumulates formatted JSON.
+type jsonFormatter struct {
+	options    FormatOptions
+	indentUnit string
+	sb         strings.Builder
+	out        io.Writer // if set, the output is periodically flushed here
+	err        error
+	column     int
+	singleLine bool // lists and maps are written on one line, as [1, 2]
+
+	// maxLength, if set, stops an attempt to write on one line once the output gets longer.
+	maxLength int
+	exceeded  bool
+
+	// commentsMemo remembers which subtrees have comments, so that each node is only inspected once.
+	commentsMemo map[OJsonObject]bool
+}
+
+func newJSONFormatter(options FormatOptions, out io.Writer) *jsonFormatter {
+	indentUnit := "    "
+	if options.UseTabs {
+		indentUnit = "\t"
+	} else if options.IndentWidth > 0 {
+		indentUnit = strings.Repeat(" ", options.IndentWidth)
+	}
+	return &jsonFormatter{
+		options:    options,
+		indentUnit: indentUnit,
+		out:        out,
+	}
 }
 
-func addIndent(sb *strings.Builder, indent int) {
-	for i := 0; i < indent; i++ {
-		sb.WriteString("    ")
+// full says whether an attempt to write on one line went over maxLength, after which there is no point in writing more.
+func (f *jsonFormatter) full(extra int) bool {
+	if f.maxLength > 0 && f.sb.Len()+extra > f.maxLength {
+		f.exceeded = true
 	}
+	return f.exceeded
 }
 
-func (j *OJsonMap) writeJSON(sb *strings.Builder, indent int) {
-	if j.Size() == 0 {
-		sb.WriteString("{}")
+func (f *jsonFormatter) writeString(s string) {
+	if f.full(len(s)) {
 		return
 	}
+	f.sb.WriteString(s)
+	if lastNewline := strings.LastIndexByte(s, '\n'); lastNewline >= 0 {
+		f.column = len(s) - lastNewline - 1
+	} else {
+		f.column += len(s)
+	}
+}
 
-	sb.WriteString("{")
-	for i, child := range j.OrderedKV {
-		sb.WriteString("\n")
-		addIndent(sb, indent+1)
-		sb.WriteString("\"")
-		sb.WriteString(child.Key)
-		sb.WriteString("\": ")
-		child.Value.writeJSON(sb, indent+1)
-		if i < len(j.OrderedKV)-1 {
-			sb.WriteString(",")
+func (f *jsonFormatter) writeStringLiteral(value string) {
+	// escaping never makes the literal shorter
+	if f.full(len(value) + 2) {
+		return
+	}
+	before := f.sb.Len()
+	writeJSONString(&f.sb, value)
+	f.column += f.sb.Len() - before // escaped strings never contain new lines
+}
+
+// newline starts a new line, with the given indentation.
+// Nothing is written in compact mode.
+func (f *jsonFormatter) newline(indent int) {
+	if f.options.Compact || f.singleLine {
+		return
+	}
+	f.flush(false)
+	f.writeString("\n")
+	f.writeString(strings.Repeat(f.indentUnit, indent))
+}
+
+// flush passes the output on to the io.Writer, if there is one and enough output was accumulated.
+func (f *jsonFormatter) flush(force bool) {
+	if f.out == nil || f.err != nil || (!force && f.sb.Len() < flushThreshold) {
+		return
+	}
+	_, f.err = io.WriteString(f.out, f.sb.String())
+	f.sb.Reset()
+}
+
+// keyValueSeparator yields what goes between a key and its value.
+func (f *jsonFormatter) keyValueSeparator() string {
+	if f.options.Compact {
+		return ":"
+	}
+	return ": "
+}
+
+// itemSeparator yields what goes after each item of a list or map, except the last.
+func (f *jsonFormatter) itemSeparator() string {
+	if f.singleLine && !f.options.Compact {
+		return ", "
+	}
+	return ","
+}
+
+func (f *jsonFormatter) writeComments(comments []string, indent int) {
+	if f.options.Compact {
+		return
+	}
+	for _, comment := range comments {
+		f.writeString(comment)
+		if strings.HasPrefix(comment, "//") {
+			f.newline(indent)
+		} else {
+			f.writeString(" ")
 		}
 	}
-	sb.WriteString("\n")
-	addIndent(sb, indent)
-	sb.WriteString("}")
 }
 
-func (j *OJsonList) writeJSON(sb *strings.Builder, indent int) {
+func (f *jsonFormatter) writeClosingComments(comments []string, indent int) {
+	if f.options.Compact {
+		return
+	}
+	for _, comment := range comments {
+		f.newline(indent)
+		f.writeString(comment)
+	}
+}
+
+// orderedKV yields the map entries, in the order in which they should be written.
+func (f *jsonFormatter) orderedKV(j *OJsonMap) []*OJsonKeyValuePair {
+	if !f.options.SortKeys {
+		return j.OrderedKV
+	}
+	sorted := append([]*OJsonKeyValuePair{}, j.OrderedKV...)
+	sort.SliceStable(sorted, func(i, k int) bool {
+		return sorted[i].Key < sorted[k].Key
+	})
+	return sorted
+}
+
+// tryInline writes a map or list on the current line, if allowed and if it fits.
+func (f *jsonFormatter) tryInline(j OJsonObject) bool {
+	if f.options.Compact || f.singleLine || f.options.MaxLineWidth <= 0 || f.hasComments(j) {
+		return false
+	}
+	// leave room for a comma after the value
+	maxLength := f.options.MaxLineWidth - f.column - 1
+	if maxLength <= 0 {
+		return false
+	}
+	inline := newJSONFormatter(f.options, nil)
+	inline.singleLine = true
+	inline.maxLength = maxLength
+	j.writeJSON(inline, 0)
+	if inline.exceeded {
+		return false
+	}
+	f.writeString(inline.sb.String())
+	return true
+}
+
+// hasComments yields true if there are comments anywhere in the tree.
+// Results are memoized, since every ancestor of a node asks about it.
+func (f *jsonFormatter) hasComments(jobj OJsonObject) bool {
+	if f.commentsMemo == nil {
+		f.commentsMemo = make(map[OJsonObject]bool)
+	}
+	if found, known := f.commentsMemo[jobj]; known {
+		return found
+	}
+	found := len(commentsOf(jobj)) > 0
+	switch j := jobj.(type) {
+	case *OJsonMap:
+		found = found || len(j.ClosingComments) > 0
+		for _, kv := range j.OrderedKV {
+			// no shortcut, so that all children are memoized
+			childFound := f.hasComments(kv.Value)
+			found = found || len(kv.Comments) > 0 || childFound
+		}
+	case *OJsonList:
+		found = found || len(j.ClosingComments) > 0
+		for _, item := range j.AsList() {
+			childFound := f.hasComments(item)
+			found = found || childFound
+		}
+	}
+	f.commentsMemo[jobj] = found
+	return found
+}
+
+// JSONString returns a formatted string representation of an ordered JSON.
+// Comments found by the JSONC parser are also written.
+func JSONString(j OJsonObject) string {
+	return JSONStringWithOptions(j, FormatOptions{})
+}
+
+// JSONStringWithOptions returns a string representation of an ordered JSON, in the given style.
+// The result always ends with a new line.
+func JSONStringWithOptions(j OJsonObject, options FormatOptions) string {
+	f := newJSONFormatter(options, nil)
+	f.writeValue(j, 0)
+	f.sb.WriteString("\n")
+	return f.sb.String()
+}
+
+// WriteJSON writes an ordered JSON to a writer, in the given style.
+// The output is passed on in chunks, the whole document never needs to be in memory as text.
+func WriteJSON(w io.Writer, j OJsonObject, options FormatOptions) error {
+	f := newJSONFormatter(options, w)
+	f.writeValue(j, 0)
+	f.sb.WriteString("\n")
+	f.flush(true)
+	return f.err
+}
+
+// writeValue writes the comments preceding a value, then the value itself.
+func (f *jsonFormatter) writeValue(j OJsonObject, indent int) {
+	f.writeComments(commentsOf(j), indent)
+	j.writeJSON(f, indent)
+}
+
+// scalarJSONString formats a string, number, bool or null, without its comments.
+func scalarJSONString(j OJsonObject) string {
+	f := newJSONFormatter(FormatOptions{}, nil)
+	j.writeJSON(f, 0)
+	return f.sb.String()
+}
+
+func (j *OJsonMap) writeJSON(f *jsonFormatter, indent int) {
+	if j.Size() == 0 && (len(j.ClosingComments) == 0 || f.options.Compact) {
+		f.writeString("{}")
+		return
+	}
+	if f.tryInline(j) {
+		return
+	}
+
+	f.writeString("{")
+	orderedKV := f.orderedKV(j)
+	for i, child := range orderedKV {
+		if f.exceeded {
+			return
+		}
+		f.newline(indent + 1)
+		f.writeComments(child.Comments, indent+1)
+		f.writeStringLiteral(child.Key)
+		f.writeString(f.keyValueSeparator())
+		f.writeValue(child.Value, indent+1)
+		if i < len(orderedKV)-1 {
+			f.writeString(f.itemSeparator())
+		}
+	}
+	f.writeClosingComments(j.ClosingComments, indent+1)
+	f.newline(indent)
+	f.writeString("}")
+}
+
+func (j *OJsonList) writeJSON(f *jsonFormatter, indent int) {
 	collection := j.AsList()
-	if len(collection) == 0 {
-		sb.WriteString("[]")
+	if len(collection) == 0 && (len(j.ClosingComments) == 0 || f.options.Compact) {
+		f.writeString("[]")
+		return
+	}
+	if f.tryInline(j) {
 		return
 	}
 
-	sb.WriteString("[")
+	f.writeString("[")
 	for i, child := range collection {
-		sb.WriteString("\n")
-		addIndent(sb, indent+1)
-		child.writeJSON(sb, indent+1)
+		if f.exceeded {
+			return
+		}
+		f.newline(indent + 1)
+		f.writeValue(child, indent+1)
 		if i < len(collection)-1 {
-			sb.WriteString(",")
+			f.writeString(f.itemSeparator())
 		}
 	}
-	sb.WriteString("\n")
-	addIndent(sb, indent)
-	sb.WriteString("]")
+	f.writeClosingComments(j.ClosingComments, indent+1)
+	f.newline(indent)
+	f.writeString("]")
+}
+
+func (j *OJsonString) writeJSON(f *jsonFormatter, indent int) {
+	f.writeStringLiteral(j.Value)
+}
+
+func (j *OJsonBool) writeJSON(f *jsonFormatter, indent int) {
+	if j.Value {
+		f.writeString("true")
+	} else {
+		f.writeString("false")
+	}
 }
 
-func (j *OJsonString) writeJSON(sb *strings.Builder, indent int) {
-	sb.WriteString(fmt.Sprintf("\"%s\"", j.Value))
+func (j *OJsonNumber) writeJSON(f *jsonFormatter, indent int) {
+	f.writeString(j.Value)
 }
 
-func (j *OJsonBool) writeJSON(sb *strings.Builder, indent int) {
-	sb.WriteString(fmt.Sprintf("%v", bool(*j)))
+func (j *OJsonNull) writeJSON(f *jsonFormatter, indent int) {
+	f.writeString("null")
 }
@@ -0,0 +1,276 @@
+package orderedjson
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenBeginMap
+	tokenEndMap
+	tokenBeginList
+	tokenEndList
+	tokenColon
+	tokenComma
+	tokenString
+	tokenLiteral
+)
+
+// token is a lexical unit of JSON.
+// For strings, text is the raw content between the quotes, not yet decoded.
+// For literals (numbers, booleans, null), text is the literal as it appears in the input.
+type token struct {
+	kind tokenKind
+	text string
+	pos  Position
+}
+
+// firstChar yields the first character of the token, for error messages.
+func (t token) firstChar() byte {
+	switch t.kind {
+	case tokenBeginMap:
+		return '{'
+	case tokenEndMap:
+		return '}'
+	case tokenBeginList:
+		return '['
+	case tokenEndList:
+		return ']'
+	case tokenColon:
+		return ':'
+	case tokenComma:
+		return ','
+	case tokenString:
+		return '"'
+	default:
+		return t.text[0]
+	}
+}
+
+// tokenizer splits a stream of JSON into tokens.
+// It only keeps the current token in memory, so input of any size can be processed.
+type tokenizer struct {
+	reader          *bufio.Reader
+	allowComments   bool
+	maxDocumentSize int
+	maxTokenLength  int
+	pos             Position // position of the next byte
+	prevPos         Position // position of the last byte read, for unreading
+	buffer          []byte
+	comments        []string // comments not yet claimed by any node
+}
+
+func newTokenizer(reader io.Reader, options ParseOptions) *tokenizer {
+	return &tokenizer{
+		reader:          bufio.NewReader(reader),
+		allowComments:   options.Dialect == DialectJSONC,
+		maxDocumentSize: options.MaxDocumentSize,
+		maxTokenLength:  options.MaxStringLength,
+		pos:             Position{Line: 1, Column: 1, Offset: 0},
+	}
+}
+
+func (t *tokenizer) readByte() (byte, error) {
+	c, err := t.reader.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if t.maxDocumentSize > 0 && t.pos.Offset >= t.maxDocumentSize {
+		return 0, ErrorAt(t.pos, &LimitError{Limit: LimitDocumentSize, Max: t.maxDocumentSize})
+	}
+	t.prevPos = t.pos
+	if c == '\n' {
+		t.pos.Line++
+		t.pos.Column = 1
+	} else {
+		t.pos.Column++
+	}
+	t.pos.Offset++
+	return c, nil
+}
+
+func (t *tokenizer) unreadByte() {
+	_ = t.reader.UnreadByte()
+	t.pos = t.prevPos
+}
+
+func (t *tokenizer) unexpectedEOF() error {
+	return errorAtf(t.pos, "unexpected end of input")
+}
+
+// appendToBuffer adds a character to the token being read, checking the length limit.
+func (t *tokenizer) appendToBuffer(c byte, startPos Position) error {
+	if t.maxTokenLength > 0 && len(t.buffer) >= t.maxTokenLength {
+		return ErrorAt(startPos, &LimitError{Limit: LimitStringLength, Max: t.maxTokenLength})
+	}
+	t.buffer = append(t.buffer, c)
+	return nil
+}
+
+// takeComments yields the comments read since the last call.
+func (t *tokenizer) takeComments() []string {
+	comments := t.comments
+	t.comments = nil
+	return comments
+}
+
+// next reads the next token, skipping whitespace and comments.
+func (t *tokenizer) next() (token, error) {
+	for {
+		startPos := t.pos
+		c, err := t.readByte()
+		if err == io.EOF {
+			return token{kind: tokenEOF, pos: startPos}, nil
+		}
+		if err != nil {
+			return token{}, err
+		}
+
+		switch c {
+		case ' ', '\n', '\r', '\t':
+			continue
+		case '{':
+			return token{kind: tokenBeginMap, pos: startPos}, nil
+		case '}':
+			return token{kind: tokenEndMap, pos: startPos}, nil
+		case '[':
+			return token{kind: tokenBeginList, pos: startPos}, nil
+		case ']':
+			return token{kind: tokenEndList, pos: startPos}, nil
+		case ':':
+			return token{kind: tokenColon, pos: startPos}, nil
+		case ',':
+			return token{kind: tokenComma, pos: startPos}, nil
+		case '"':
+			return t.readString(startPos)
+		case '/':
+			if t.allowComments {
+				isComment, err := t.readComment(startPos)
+				if err != nil {
+					return token{}, err
+				}
+				if isComment {
+					continue
+				}
+			}
+		}
+		return t.readLiteral(c, startPos)
+	}
+}
+
+// readString reads a string literal, the opening quote was already consumed.
+func (t *tokenizer) readString(startPos Position) (token, error) {
+	t.buffer = t.buffer[:0]
+	escaped := false
+	for {
+		c, err := t.readByte()
+		if err == io.EOF {
+			return token{}, t.unexpectedEOF()
+		}
+		if err != nil {
+			return token{}, err
+		}
+		if escaped {
+			escaped = false
+		} else if c == '\\' {
+			escaped = true
+		} else if c == '"' {
+			return token{kind: tokenString, text: string(t.buffer), pos: startPos}, nil
+		}
+		if err := t.appendToBuffer(c, startPos); err != nil {
+			return token{}, err
+		}
+	}
+}
+
+// readLiteral reads a number, boolean or null, up to the next delimiter.
+// Validating the literal is left to the parser.
+func (t *tokenizer) readLiteral(first byte, startPos Position) (token, error) {
+	t.buffer = append(t.buffer[:0], first)
+	for {
+		c, err := t.readByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return token{}, err
+		}
+		if t.isDelimiter(c) {
+			t.unreadByte()
+			break
+		}
+		if err := t.appendToBuffer(c, startPos); err != nil {
+			return token{}, err
+		}
+	}
+	return token{kind: tokenLiteral, text: string(t.buffer), pos: startPos}, nil
+}
+
+func (t *tokenizer) isDelimiter(c byte) bool {
+	switch c {
+	case ' ', '\n', '\r', '\t', '{', '}', '[', ']', ':', ',', '"':
+		return true
+	case '/':
+		return t.allowComments
+	default:
+		return false
+	}
+}
+
+// readComment reads a comment, the leading slash was already consumed.
+// Yields false if the slash does not start a comment.
+func (t *tokenizer) readComment(startPos Position) (bool, error) {
+	c, err := t.readByte()
+	if err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	t.buffer = append(t.buffer[:0], '/', c)
+	switch c {
+	case '/':
+		for {
+			c, err = t.readByte()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return false, err
+			}
+			if c == '\n' || c == '\r' {
+				t.unreadByte()
+				break
+			}
+			if err := t.appendToBuffer(c, startPos); err != nil {
+				return false, err
+			}
+		}
+	case '*':
+		for {
+			c, err = t.readByte()
+			if err == io.EOF {
+				return false, ErrorAt(startPos, errors.New("unterminated comment"))
+			}
+			if err != nil {
+				return false, err
+			}
+			if err := t.appendToBuffer(c, startPos); err != nil {
+				return false, err
+			}
+			if c == '/' && len(t.buffer) >= 4 && t.buffer[len(t.buffer)-2] == '*' {
+				break
+			}
+		}
+	default:
+		t.unreadByte()
+		return false, nil
+	}
+	t.comments = append(t.comments, string(t.buffer))
+	return true, nil
+}
@@ -0,0 +1,73 @@
+package orderedjson
+
+// VisitFunc is called by Walk for every object in the tree, together with its location.
+// Returning false skips the children of the object.
+type VisitFunc func(path Pointer, jobj OJsonObject) bool
+
+// Walk visits all objects in the tree, depth-first, in document order.
+// Parents are visited before their children.
+func Walk(root OJsonObject, visit VisitFunc) {
+	walk(Pointer{}, root, visit)
+}
+
+func walk(path Pointer, jobj OJsonObject, visit VisitFunc) {
+	if !visit(path, jobj) {
+		return
+	}
+	switch j := jobj.(type) {
+	case *OJsonMap:
+		for _, kv := range j.OrderedKV {
+			walk(path.Append(kv.Key), kv.Value, visit)
+		}
+	case *OJsonList:
+		for i, item := range j.Items {
+			walk(path.AppendIndex(i), item, visit)
+		}
+	}
+}
+
+// TransformFunc yields the replacement for an object, or the object itself to keep it.
+// Returning nil removes the object from its parent map or list.
+type TransformFunc func(path Pointer, jobj OJsonObject) (OJsonObject, error)
+
+// Transform rewrites a tree bottom-up: children are transformed before their parents.
+// Maps and lists are modified in place. Yields the new root, nil if the root was removed.
+func Transform(root OJsonObject, transform TransformFunc) (OJsonObject, error) {
+	return transformTree(Pointer{}, root, transform)
+}
+
+func transformTree(path Pointer, jobj OJsonObject, transform TransformFunc) (OJsonObject, error) {
+	switch j := jobj.(type) {
+	case *OJsonMap:
+		var keptKV []*OJsonKeyValuePair
+		for _, kv := range j.OrderedKV {
+			newValue, err := transformTree(path.Append(kv.Key), kv.Value, transform)
+			if err != nil {
+				return nil, err
+			}
+			if newValue != nil {
+				kv.Value = newValue
+				keptKV = append(keptKV, kv)
+			}
+		}
+		if len(keptKV) != len(j.OrderedKV) {
+			j.OrderedKV = keptKV
+			j.RefreshKeySet()
+		}
+	case *OJsonList:
+		var keptItems []OJsonObject
+		for i, item := range j.Items {
+			newItem, err := transformTree(path.AppendIndex(i), item, transform)
+			if err != nil {
+				return nil, err
+			}
+			if newItem != nil {
+				keptItems = append(keptItems, newItem)
+			}
+		}
+		if len(j.Items) > 0 {
+			j.Items = keptItems
+		}
+	}
+	return transform(path, jobj)
+}
@@ -0,0 +1,325 @@
+package orderedjson
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseYAML parses YAML into an ordered JSON tree, preserving the order of keys.
+// Comments are kept, converted to "//" line comments, so they can also be written as JSONC.
+// Comments at the end of a line are moved before the value they follow, or before its key.
+//
+// Numbers in a form that JSON does not support, e.g. 0x1F or 1_000, are kept as strings, exactly as written.
+// This way, mandos values like 0x1234 reach the value interpreter unchanged, even without quotes.
+// Anchors and aliases are expanded. Tags other than the standard ones are ignored.
+func ParseYAML(input []byte) (OJsonObject, error) {
+	return ParseYAMLWithOptions(input, ParseOptions{})
+}
+
+// ParseYAMLWithOptions parses YAML, like ParseYAML.
+// The duplicate key policy and the limits in the options are applied, the dialect is ignored.
+func ParseYAMLWithOptions(input []byte, options ParseOptions) (OJsonObject, error) {
+	if options.MaxDocumentSize > 0 && len(input) > options.MaxDocumentSize {
+		return nil, ErrorAt(newYAMLLineIndex(input).position(1, 1), &LimitError{Limit: LimitDocumentSize, Max: options.MaxDocumentSize})
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(input, &document); err != nil {
+		return nil, err
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return nil, errors.New("empty YAML document")
+	}
+	c := &yamlConverter{
+		options:        options,
+		lines:          newYAMLLineIndex(input),
+		remainingNodes: yamlNodesPerByte * (len(input) + 1),
+	}
+	root, err := c.convert(document.Content[0], 1)
+	if err != nil {
+		return nil, err
+	}
+	attachComments(root, append(yamlComments(document.HeadComment), commentsOf(root)...))
+	return root, nil
+}
+
+// yamlLineIndex converts YAML positions to byte-based positions.
+type yamlLineIndex struct {
+	input      []byte
+	lineStarts []int
+}
+
+func newYAMLLineIndex(input []byte) *yamlLineIndex {
+	index := &yamlLineIndex{input: input, lineStarts: []int{0}}
+	for i, c := range input {
+		if c == '\n' {
+			index.lineStarts = append(index.lineStarts, i+1)
+		}
+	}
+	return index
+}
+
+// position yields the position of a YAML node. The YAML parser counts columns in characters, not bytes.
+func (index *yamlLineIndex) position(line int, column int) Position {
+	if line < 1 || line > len(index.lineStarts) {
+		return Position{}
+	}
+	offset := index.lineStarts[line-1]
+	for i := 1; i < column && offset < len(index.input); i++ {
+		_, size := utf8.DecodeRune(index.input[offset:])
+		offset += size
+	}
+	return Position{
+		Line:   line,
+		Column: offset - index.lineStarts[line-1] + 1,
+		Offset: offset,
+	}
+}
+
+type yamlConverter struct {
+	options ParseOptions
+	lines   *yamlLineIndex
+	// guards against aliases referring to aliases, expanding exponentially ("billion laughs")
+	remainingNodes int
+}
+
+// yamlNodesPerByte bounds the size of the tree, relative to the size of the input.
+// Without aliases, there is at most one node per byte.
+const yamlNodesPerByte = 16
+
+func (c *yamlConverter) convert(node *yaml.Node, depth int) (OJsonObject, error) {
+	pos := c.lines.position(node.Line, node.Column)
+	c.remainingNodes--
+	if c.remainingNodes < 0 {
+		return nil, errorAtf(pos, "YAML aliases expand to too many values")
+	}
+	comments := append(yamlComments(node.HeadComment), yamlComments(node.LineComment)...)
+
+	switch node.Kind {
+	case yaml.AliasNode:
+		return c.convert(node.Alias, depth)
+	case yaml.MappingNode, yaml.SequenceNode:
+		if maxDepth := c.options.maxDepth(); depth > maxDepth {
+			return nil, ErrorAt(pos, &LimitError{Limit: LimitDepth, Max: maxDepth})
+		}
+		if node.Kind == yaml.MappingNode {
+			return c.convertMapping(node, pos, comments, depth)
+		}
+		return c.convertSequence(node, pos, comments, depth)
+	case yaml.ScalarNode:
+		if c.options.MaxStringLength > 0 && len(node.Value) > c.options.MaxStringLength {
+			return nil, ErrorAt(pos, &LimitError{Limit: LimitStringLength, Max: c.options.MaxStringLength})
+		}
+		return convertYAMLScalar(node, pos, comments), nil
+	default:
+		return nil, errorAtf(pos, "unexpected YAML node")
+	}
+}
+
+func convertYAMLScalar(node *yaml.Node, pos Position, comments []string) OJsonObject {
+	switch node.ShortTag() {
+	case "!!null":
+		return &OJsonNull{Pos: pos, Comments: comments}
+	case "!!bool":
+		return &OJsonBool{Value: strings.EqualFold(node.Value, "true"), Pos: pos, Comments: comments}
+	case "!!int", "!!float":
+		if isValidNumber(node.Value) {
+			return &OJsonNumber{Value: node.Value, Pos: pos, Comments: comments}
+		}
+	}
+	return &OJsonString{Value: node.Value, Pos: pos, Comments: comments}
+}
+
+func (c *yamlConverter) convertMapping(node *yaml.Node, pos Position, comments []string, depth int) (OJsonObject, error) {
+	result := NewMap()
+	result.Pos = pos
+	result.Comments = comments
+
+	var pending []string // foot comments, carried over to the next entry
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		keyPos := c.lines.position(keyNode.Line, keyNode.Column)
+		if keyNode.ShortTag() == "!!merge" {
+			return nil, errorAtf(keyPos, "YAML merge keys are not supported")
+		}
+		if keyNode.Kind != yaml.ScalarNode {
+			return nil, errorAtf(keyPos, "map key must be a scalar")
+		}
+
+		value, err := c.convert(valueNode, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		keyComments := append(pending, yamlComments(keyNode.HeadComment)...)
+		keyComments = append(keyComments, yamlComments(keyNode.LineComment)...)
+		if valueNode.Kind == yaml.ScalarNode {
+			// e.g. a comment at the end of the line, better placed before the key
+			keyComments = append(keyComments, commentsOf(value)...)
+			attachComments(value, nil)
+		}
+		pending = append(yamlComments(keyNode.FootComment), yamlComments(valueNode.FootComment)...)
+
+		if kv := result.put(keyNode.Value, keyPos, value); kv != nil {
+			kv.Comments = keyComments
+			if c.options.MaxKeyCount > 0 && result.Size() > c.options.MaxKeyCount {
+				return nil, ErrorAt(keyPos, &LimitError{Limit: LimitKeyCount, Max: c.options.MaxKeyCount})
+			}
+			continue
+		}
+		existing := result.findKV(keyNode.Value)
+		switch c.options.DuplicateKeys {
+		case DuplicateKeysFirstWins:
+		case DuplicateKeysLastWins:
+			existing.Value = value
+		default:
+			return nil, ErrorAt(keyPos, &DuplicateKeyError{Key: keyNode.Value, FirstPos: existing.KeyPos})
+		}
+	}
+	result.ClosingComments = append(pending, yamlComments(node.FootComment)...)
+	return result, nil
+}
+
+func (c *yamlConverter) convertSequence(node *yaml.Node, pos Position, comments []string, depth int) (OJsonObject, error) {
+	result := &OJsonList{Pos: pos, Comments: comments}
+
+	var pending []string // foot comments, carried over to the next item
+	for _, itemNode := range node.Content {
+		item, err := c.convert(itemNode, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		attachComments(item, append(pending, commentsOf(item)...))
+		pending = yamlComments(itemNode.FootComment)
+		result.Items = append(result.Items, item)
+	}
+	result.ClosingComments = append(pending, yamlComments(node.FootComment)...)
+	return result, nil
+}
+
+// attachComments replaces the comments preceding a node.
+func attachComments(jobj OJsonObject, comments []string) {
+	if len(comments) == 0 {
+		comments = nil
+	}
+	switch j := jobj.(type) {
+	case *OJsonMap:
+		j.Comments = comments
+	case *OJsonList:
+		j.Comments = comments
+	case *OJsonString:
+		j.Comments = comments
+	case *OJsonNumber:
+		j.Comments = comments
+	case *OJsonBool:
+		j.Comments = comments
+	case *OJsonNull:
+		j.Comments = comments
+	}
+}
+
+// yamlComments converts "# comment" lines to "// comment".
+func yamlComments(text string) []string {
+	var result []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			result = append(result, "//"+line[1:])
+		}
+	}
+	return result
+}
+
+// toYAMLComment converts "//" and "/* */" comments to "# comment" lines.
+func toYAMLComment(comments []string) string {
+	var lines []string
+	for _, comment := range comments {
+		if strings.HasPrefix(comment, "//") {
+			lines = append(lines, "#"+comment[2:])
+			continue
+		}
+		body := strings.TrimSuffix(strings.TrimPrefix(comment, "/*"), "*/")
+		for _, line := range strings.Split(body, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, "# "+line)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// YAMLString writes an ordered JSON tree as YAML, with 2 spaces of indentation.
+// Key order and comments are kept.
+func YAMLString(jobj OJsonObject) string {
+	var buffer bytes.Buffer
+	_ = WriteYAML(&buffer, jobj) // writing to a buffer does not fail
+	return buffer.String()
+}
+
+// WriteYAML writes an ordered JSON tree as YAML, like YAMLString.
+func WriteYAML(w io.Writer, jobj OJsonObject) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(toYAMLNode(jobj)); err != nil {
+		return fmt.Errorf("cannot write YAML: %w", err)
+	}
+	return encoder.Close()
+}
+
+func toYAMLNode(jobj OJsonObject) *yaml.Node {
+	node := &yaml.Node{HeadComment: toYAMLComment(commentsOf(jobj))}
+	switch j := jobj.(type) {
+	case *OJsonMap:
+		node.Kind = yaml.MappingNode
+		for _, kv := range j.OrderedKV {
+			keyNode := &yaml.Node{
+				Kind:        yaml.ScalarNode,
+				Tag:         "!!str",
+				Value:       kv.Key,
+				HeadComment: toYAMLComment(append(kv.Comments, commentsOf(kv.Value)...)),
+			}
+			valueNode := toYAMLNode(kv.Value)
+			valueNode.HeadComment = ""
+			node.Content = append(node.Content, keyNode, valueNode)
+		}
+		if len(node.Content) > 0 {
+			node.Content[len(node.Content)-2].FootComment = toYAMLComment(j.ClosingComments)
+		} else {
+			node.Style = yaml.FlowStyle
+		}
+	case *OJsonList:
+		node.Kind = yaml.SequenceNode
+		for _, item := range j.Items {
+			node.Content = append(node.Content, toYAMLNode(item))
+		}
+		if len(node.Content) > 0 {
+			node.Content[len(node.Content)-1].FootComment = toYAMLComment(j.ClosingComments)
+		} else {
+			node.Style = yaml.FlowStyle
+		}
+	case *OJsonString:
+		node.Kind = yaml.ScalarNode
+		node.Tag = "!!str"
+		node.Value = j.Value
+	case *OJsonNumber:
+		node.Kind = yaml.ScalarNode
+		node.Tag = "!!float"
+		if j.IsInteger() {
+			node.Tag = "!!int"
+		}
+		node.Value = j.Value
+	case *OJsonBool:
+		node.Kind = yaml.ScalarNode
+		node.Tag = "!!bool"
+		node.Value = scalarJSONString(j)
+	default:
+		node.Kind = yaml.ScalarNode
+		node.Tag = "!!null"
+		node.Value = "null"
+	}
+	return node
+}
@@ -0,0 +1,106 @@
+package orderedjson
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const yamlExample = `# scenario comment
+name: example
+steps:
+  # first step
+  - step: scCall
+    tx:
+      from: "''sender"
+      value: 0x1234 # hex stays a string
+      gasLimit: 1000
+      arguments: [1, "2", true, null, 1.5e3]
+      ünï: {}
+  - step: checkState
+`
+
+func TestParseYAML(t *testing.T) {
+	jobj, err := ParseYAML([]byte(yamlExample))
+	require.Nil(t, err)
+	require.Equal(t, `{
+    // scenario comment
+    "name": "example",
+    "steps": [
+        // first step
+        {
+            "step": "scCall",
+            "tx": {
+                "from": "''sender",
+                // hex stays a string
+                "value": "0x1234",
+                "gasLimit": 1000,
+                "arguments": [
+                    1,
+                    "2",
+                    true,
+                    null,
+                    1.5e3
+                ],
+                "ünï": {}
+            }
+        },
+        {
+            "step": "checkState"
+        }
+    ]
+}
+`, JSONString(jobj))
+
+	found, err := Find(jobj, "steps[0].tx.arguments")
+	require.Nil(t, err)
+	require.Equal(t, Position{Line: 10, Column: 18, Offset: 182}, found[0].Position())
+	require.Equal(t, byte('['), yamlExample[182])
+	// columns are counted in bytes
+	found, err = Find(jobj, `steps[0].tx["ünï"]`)
+	require.Nil(t, err)
+	require.Equal(t, "11:14", found[0].Position().String())
+	found, err = Find(jobj, "steps[1].step")
+	require.Nil(t, err)
+	require.Equal(t, "12:11", found[0].Position().String())
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	jobj, err := ParseYAML([]byte(yamlExample))
+	require.Nil(t, err)
+
+	yamlStr := YAMLString(jobj)
+	require.True(t, strings.HasPrefix(yamlStr, "# scenario comment\nname: example\nsteps:\n  # first step\n  - step: scCall\n"))
+	back, err := ParseYAML([]byte(yamlStr))
+	require.Nil(t, err)
+	require.Equal(t, JSONString(jobj), JSONString(back))
+
+	// strings that look like other values are quoted
+	jobj, err = ParseOrderedJSON([]byte(`{"a": "true", "b": "1", "c": "", "d": [], "e": 2}`))
+	require.Nil(t, err)
+	require.Equal(t, "a: \"true\"\nb: \"1\"\nc: \"\"\nd: []\ne: 2\n", YAMLString(jobj))
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	_, err := ParseYAML([]byte("a: 1\nb: 2\na: 3\n"))
+	require.NotNil(t, err)
+	var dupErr *DuplicateKeyError
+	require.True(t, errors.As(err, &dupErr))
+
+	_, err = ParseYAMLWithOptions([]byte("a: [[[1]]]\n"), ParseOptions{MaxDepth: 3})
+	require.Equal(t, "1:6: maximum nesting depth of 3 exceeded", err.Error())
+
+	laughs := "a: &a [x, x, x, x, x, x, x, x, x, x]\n"
+	for i := 'b'; i <= 'j'; i++ {
+		prev := string(i - 1)
+		laughs += string(i) + ": &" + string(i) + " [*" + prev + ", *" + prev + ", *" + prev + ", *" + prev + ", *" + prev + ", *" + prev + ", *" + prev + ", *" + prev + "]\n"
+	}
+	_, err = ParseYAML([]byte(laughs))
+	require.NotNil(t, err)
+	require.True(t, strings.HasSuffix(err.Error(), "YAML aliases expand to too many values"))
+
+	_, err = ParseYAML([]byte("a: [1\n"))
+	require.NotNil(t, err)
+}
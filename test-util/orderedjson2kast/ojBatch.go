@@ -0,0 +1,239 @@
+package orderedjson2kast
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	mtp "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/testpaths"
+	oj "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson"
+)
+
+// KastExtension replaces the ".json" extension of the converted files.
+const KastExtension = ".kast"
+
+// KoreExtension replaces the ".json" extension of the files converted to KORE.
+const KoreExtension = ".kore"
+
+// BatchOptions configures ConvertDirectory.
+type BatchOptions struct {
+	// AllowedSuffixes selects the files to convert. By default, ".test.json" and ".scen.json".
+	AllowedSuffixes []string
+
+	// ExcludedFilePatterns are relative to the general test path, like for the test runners.
+	ExcludedFilePatterns []string
+
+	// OutputPath is the root of a tree that mirrors the general test path.
+	// If empty, KAST files are written next to the files they come from.
+	OutputPath string
+
+	// Parallelism is the number of files converted at the same time, the number of CPUs if 0.
+	Parallelism int
+
+	// Force converts all files, even if their KAST is newer than them.
+	Force bool
+
+	// CodeProcessor assembles the code in the tests, which stays as written if nil.
+	// Results are memoized, so each contract gets assembled once for the whole directory.
+	CodeProcessor CodeProcessor
+
+	// Profile gives the K labels, IELEProfile() if nil.
+	Profile *LabelProfile
+
+	// Kore writes KORE files instead of KAST.
+	Kore bool
+}
+
+// BatchSummary counts the outcomes of ConvertDirectory.
+type BatchSummary struct {
+	Converted int
+	UpToDate  int
+	Skipped   int
+	// Failures holds an error for each file that could not be converted, prefixed with its path.
+	Failures []error
+}
+
+// String yields a one line summary, in the style of the test runners.
+func (s *BatchSummary) String() string {
+	return fmt.Sprintf("Converted: %d. Up to date: %d. Skipped: %d. Failed: %d.",
+		s.Converted, s.UpToDate, s.Skipped, len(s.Failures))
+}
+
+type batchOutcome int
+
+const (
+	batchConverted batchOutcome = iota
+	batchUpToDate
+	batchFailed
+)
+
+type batchJob struct {
+	inputPath  string
+	outputPath string
+	outcome    batchOutcome
+	err        error
+}
+
+// ConvertDirectory walks a directory and converts all JSON tests and scenarios found to KAST, or KORE.
+// Files are converted in parallel. Only the input file is compared with the KAST to decide if it is up to date,
+// changed contracts need Force.
+// The error is only for problems walking the directory, conversion failures are in the summary.
+func ConvertDirectory(generalTestPath string, specificTestPath string, options BatchOptions) (*BatchSummary, error) {
+	allowedSuffixes := options.AllowedSuffixes
+	if len(allowedSuffixes) == 0 {
+		allowedSuffixes = []string{".test.json", ".scen.json"}
+	}
+	summary := &BatchSummary{}
+	var jobs []*batchJob
+
+	mainDirPath := path.Join(generalTestPath, specificTestPath)
+	err := filepath.Walk(mainDirPath, func(testFilePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !mtp.HasAnySuffix(testFilePath, allowedSuffixes) {
+			return nil
+		}
+		excluded, err := mtp.IsExcluded(options.ExcludedFilePatterns, testFilePath, generalTestPath)
+		if err != nil {
+			return err
+		}
+		if excluded {
+			summary.Skipped++
+			return nil
+		}
+		jobs = append(jobs, &batchJob{
+			inputPath:  testFilePath,
+			outputPath: kastOutputPath(testFilePath, generalTestPath, options),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	converter := newBatchConverter(options)
+	parallelism := options.Parallelism
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+	jobChannel := make(chan *batchJob)
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobChannel {
+				converter.run(job)
+			}
+		}()
+	}
+	for _, job := range jobs {
+		jobChannel <- job
+	}
+	close(jobChannel)
+	wg.Wait()
+
+	for _, job := range jobs {
+		switch job.outcome {
+		case batchConverted:
+			summary.Converted++
+		case batchUpToDate:
+			summary.UpToDate++
+		default:
+			shortPath := mtp.ShortenTestPath(job.inputPath, generalTestPath)
+			if _, isPositioned := job.err.(*oj.PositionError); isPositioned {
+				summary.Failures = append(summary.Failures, fmt.Errorf("%s:%w", shortPath, job.err))
+			} else {
+				summary.Failures = append(summary.Failures, fmt.Errorf("%s: %w", shortPath, job.err))
+			}
+		}
+	}
+	return summary, nil
+}
+
+// kastOutputPath replaces the ".json" extension, and moves the file to the output tree, if any.
+func kastOutputPath(testFilePath string, generalTestPath string, options BatchOptions) string {
+	extension := KastExtension
+	if options.Kore {
+		extension = KoreExtension
+	}
+	result := strings.TrimSuffix(testFilePath, filepath.Ext(testFilePath)) + extension
+	if len(options.OutputPath) == 0 {
+		return result
+	}
+	relativePath, err := filepath.Rel(generalTestPath, result)
+	if err != nil {
+		relativePath = filepath.Base(result)
+	}
+	return filepath.Join(options.OutputPath, relativePath)
+}
+
+type batchConverter struct {
+	force       bool
+	processCode ProcessCodeFunc
+	profile     *LabelProfile
+	kore        bool
+}
+
+func newBatchConverter(options BatchOptions) *batchConverter {
+	processCode := func(testPath string, value string) (string, error) {
+		return value, nil
+	}
+	if options.CodeProcessor != nil {
+		processCode = MemoizeProcessCode(options.CodeProcessor.ProcessCode)
+	}
+	profile := options.Profile
+	if profile == nil {
+		profile = IELEProfile()
+	}
+	return &batchConverter{
+		force:       options.Force,
+		processCode: processCode,
+		profile:     profile,
+		kore:        options.Kore,
+	}
+}
+
+func (c *batchConverter) run(job *batchJob) {
+	inputInfo, err := os.Stat(job.inputPath)
+	if err != nil {
+		job.outcome, job.err = batchFailed, err
+		return
+	}
+	if !c.force {
+		if outputInfo, err := os.Stat(job.outputPath); err == nil && !outputInfo.ModTime().Before(inputInfo.ModTime()) {
+			job.outcome = batchUpToDate
+			return
+		}
+	}
+
+	data, err := ioutil.ReadFile(job.inputPath)
+	if err != nil {
+		job.outcome, job.err = batchFailed, err
+		return
+	}
+	convert := ConvertOrderedJSONToKastWithProfile
+	if c.kore {
+		convert = ConvertOrderedJSONToKoreWithProfile
+	}
+	output, err := convert(data, job.inputPath, c.processCode, c.profile)
+	if err != nil {
+		job.outcome, job.err = batchFailed, err
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(job.outputPath), 0755); err != nil {
+		job.outcome, job.err = batchFailed, err
+		return
+	}
+	if err := ioutil.WriteFile(job.outputPath, []byte(output), 0644); err != nil {
+		job.outcome, job.err = batchFailed, err
+		return
+	}
+	job.outcome = batchConverted
+}
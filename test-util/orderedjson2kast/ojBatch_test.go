@@ -0,0 +1,46 @@
+package orderedjson2kast
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kastbatch")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	require.Nil(t, os.MkdirAll(filepath.Join(dir, "tests", "skipped"), 0755))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "tests", "a.scen.json"), []byte(`{"steps": []}`), 0644))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "tests", "b.test.json"), []byte(`{"code": "file:x.iele"}`), 0644))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "tests", "c.scen.json"), []byte(`{`), 0644))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "tests", "skipped", "d.scen.json"), []byte(`{}`), 0644))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "tests", "notes.json"), []byte(`{}`), 0644))
+
+	options := BatchOptions{
+		ExcludedFilePatterns: []string{"skipped/*"},
+		OutputPath:           filepath.Join(dir, "out"),
+		CodeProcessor: ProcessCodeFunc(func(testPath string, value string) (string, error) {
+			return "assembled", nil
+		}),
+	}
+	summary, err := ConvertDirectory(filepath.Join(dir, "tests"), "", options)
+	require.Nil(t, err)
+	require.Equal(t, "Converted: 2. Up to date: 0. Skipped: 1. Failed: 1.", summary.String())
+	require.Equal(t, "c.scen.json:1:2: unexpected end of input", summary.Failures[0].Error())
+
+	kast, err := ioutil.ReadFile(filepath.Join(dir, "out", "b.test.kast"))
+	require.Nil(t, err)
+	require.Contains(t, string(kast), "assembled")
+
+	summary, err = ConvertDirectory(filepath.Join(dir, "tests"), "", options)
+	require.Nil(t, err)
+	require.Equal(t, "Converted: 0. Up to date: 2. Skipped: 1. Failed: 1.", summary.String())
+
+	options.ExcludedFilePatterns = []string{"["}
+	_, err = ConvertDirectory(filepath.Join(dir, "tests"), "", options)
+	require.NotNil(t, err)
+}
@@ -0,0 +1,51 @@
+package orderedjson2kast
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// CodeProcessor assembles the code in the tests, like ProcessCodeFunc.
+// Assemblers that keep state, e.g. a connection to a compiler service, can implement it directly.
+type CodeProcessor interface {
+	ProcessCode(testPath string, value string) (string, error)
+}
+
+var _ CodeProcessor = ProcessCodeFunc(nil)
+var _ CodeProcessor = (*ExternalCodeProcessor)(nil)
+
+// ProcessCode calls the function itself.
+func (f ProcessCodeFunc) ProcessCode(testPath string, value string) (string, error) {
+	return f(testPath, value)
+}
+
+// ExternalCodeProcessor assembles code by running a command,
+// with the code value from the test as its last argument, in the directory of the test.
+// The output of the command, without surrounding whitespace, replaces the value.
+// Empty values are left alone.
+type ExternalCodeProcessor struct {
+	Command string
+	Args    []string
+}
+
+// ProcessCode runs the command.
+func (p *ExternalCodeProcessor) ProcessCode(testPath string, value string) (string, error) {
+	if len(value) == 0 {
+		return value, nil
+	}
+	cmd := exec.Command(p.Command, append(append([]string{}, p.Args...), value)...)
+	cmd.Dir = testPath
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if len(message) == 0 {
+			return "", fmt.Errorf("%s: %w", p.Command, err)
+		}
+		return "", fmt.Errorf("%s: %w: %s", p.Command, err, message)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
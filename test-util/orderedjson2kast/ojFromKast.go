@@ -0,0 +1,393 @@
+package orderedjson2kast
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	oj "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson"
+)
+
+// kastTerm is a parsed KAST term, either an application of a label, or a token.
+type kastTerm struct {
+	label   string // for applications, without the backquotes
+	args    []*kastTerm
+	isToken bool
+	text    string // for tokens, unescaped
+	sort    string // for tokens
+	pos     oj.Position
+}
+
+// ConvertKastToOrderedJSON parses KAST, as produced by ConvertOrderedJSONToKast, back into ordered JSON.
+// This way, configurations dumped by the K semantics can be compared with the outputs of other executors.
+// Errors are of type *oj.PositionError, pointing into the KAST text.
+func ConvertKastToOrderedJSON(kast string) (oj.OJsonObject, error) {
+	return ConvertKastToOrderedJSONWithProfile(kast, IELEProfile())
+}
+
+// ConvertKastToOrderedJSONWithProfile is ConvertKastToOrderedJSON, for KAST that uses the labels of the given profile.
+func ConvertKastToOrderedJSONWithProfile(kast string, profile *LabelProfile) (oj.OJsonObject, error) {
+	p := &kastParser{input: kast, pos: oj.Position{Line: 1, Column: 1}}
+	term, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	p.skipWhitespace()
+	if p.pos.Offset < len(p.input) {
+		return nil, p.errorf("unexpected %q after the end of the term", p.input[p.pos.Offset])
+	}
+	return profile.kastTermToJSON(term)
+}
+
+// kastParser parses the KAST text format, e.g. `label`(arg1,arg2) or #token("1","Int").
+type kastParser struct {
+	input string
+	pos   oj.Position
+}
+
+func (p *kastParser) errorf(format string, args ...interface{}) error {
+	return oj.ErrorAt(p.pos, fmt.Errorf(format, args...))
+}
+
+func (p *kastParser) peek() byte {
+	if p.pos.Offset >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos.Offset]
+}
+
+func (p *kastParser) advance() {
+	if p.input[p.pos.Offset] == '\n' {
+		p.pos.Line++
+		p.pos.Column = 1
+	} else {
+		p.pos.Column++
+	}
+	p.pos.Offset++
+}
+
+func (p *kastParser) skipWhitespace() {
+	for p.pos.Offset < len(p.input) {
+		switch p.peek() {
+		case ' ', '\t', '\n', '\r':
+			p.advance()
+		default:
+			return
+		}
+	}
+}
+
+func (p *kastParser) expect(c byte) error {
+	p.skipWhitespace()
+	if p.pos.Offset >= len(p.input) {
+		return p.errorf("unexpected end of input, '%c' expected", c)
+	}
+	if p.peek() != c {
+		return p.errorf("unexpected %q, '%c' expected", p.peek(), c)
+	}
+	p.advance()
+	return nil
+}
+
+func (p *kastParser) parseTerm() (*kastTerm, error) {
+	p.skipWhitespace()
+	term := &kastTerm{pos: p.pos}
+	switch {
+	case p.pos.Offset >= len(p.input):
+		return nil, p.errorf("unexpected end of input, term expected")
+	case p.peek() == '`':
+		label, err := p.parseQuotedLabel()
+		if err != nil {
+			return nil, err
+		}
+		term.label = label
+	case strings.HasPrefix(p.input[p.pos.Offset:], "#token"):
+		for i := 0; i < len("#token"); i++ {
+			p.advance()
+		}
+		return p.parseTokenArgs(term)
+	default:
+		start := p.pos.Offset
+		for p.pos.Offset < len(p.input) && isKastLabelChar(p.peek()) {
+			p.advance()
+		}
+		if start == p.pos.Offset {
+			return nil, p.errorf("unexpected %q, term expected", p.peek())
+		}
+		term.label = p.input[start:p.pos.Offset]
+		if term.label == ".KList" {
+			return nil, oj.ErrorAt(term.pos, errors.New(".KList is only allowed as an argument list"))
+		}
+	}
+
+	args, err := p.parseArgs()
+	if err != nil {
+		return nil, err
+	}
+	term.args = args
+	return term, nil
+}
+
+func isKastLabelChar(c byte) bool {
+	return c == '.' || c == '_' || c == '-' || c == '#' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// parseQuotedLabel reads a label between backquotes.
+func (p *kastParser) parseQuotedLabel() (string, error) {
+	start := p.pos
+	p.advance()
+	var sb strings.Builder
+	for p.pos.Offset < len(p.input) {
+		c := p.peek()
+		p.advance()
+		switch c {
+		case '`':
+			return sb.String(), nil
+		case '\\':
+			if p.pos.Offset >= len(p.input) {
+				break
+			}
+			sb.WriteByte(p.peek())
+			p.advance()
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", oj.ErrorAt(start, errors.New("unterminated label"))
+}
+
+// parseArgs reads the arguments of a label application, in parentheses, where .KList means no arguments.
+func (p *kastParser) parseArgs() ([]*kastTerm, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	p.skipWhitespace()
+	if strings.HasPrefix(p.input[p.pos.Offset:], ".KList") {
+		for i := 0; i < len(".KList"); i++ {
+			p.advance()
+		}
+		return nil, p.expect(')')
+	}
+	var args []*kastTerm
+	for {
+		arg, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		p.skipWhitespace()
+		if p.peek() == ',' {
+			p.advance()
+			continue
+		}
+		return args, p.expect(')')
+	}
+}
+
+// parseTokenArgs reads ("text","Sort"), after #token.
+func (p *kastParser) parseTokenArgs(term *kastTerm) (*kastTerm, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	text, err := p.parseString()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(','); err != nil {
+		return nil, err
+	}
+	sort, err := p.parseString()
+	if err != nil {
+		return nil, err
+	}
+	term.isToken = true
+	term.text = text
+	term.sort = sort
+	return term, p.expect(')')
+}
+
+// parseString reads a KAST string, where only quotes and backslashes are escaped.
+func (p *kastParser) parseString() (string, error) {
+	if err := p.expect('"'); err != nil {
+		return "", err
+	}
+	start := p.pos
+	var sb strings.Builder
+	for p.pos.Offset < len(p.input) {
+		c := p.peek()
+		p.advance()
+		switch c {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if p.pos.Offset >= len(p.input) {
+				break
+			}
+			sb.WriteByte(p.peek())
+			p.advance()
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", oj.ErrorAt(start, errors.New("unterminated string"))
+}
+
+// unquoteKString decodes a K string literal, the reverse of quoteKString.
+func unquoteKString(literal string) (string, error) {
+	if len(literal) < 2 || literal[0] != '"' || literal[len(literal)-1] != '"' {
+		return "", fmt.Errorf("invalid K string literal: %s", literal)
+	}
+	body := literal[1 : len(literal)-1]
+	var sb strings.Builder
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if c != '\\' {
+			sb.WriteByte(c)
+			continue
+		}
+		i++
+		if i >= len(body) {
+			return "", fmt.Errorf("invalid escape sequence in K string literal: %s", literal)
+		}
+		switch body[i] {
+		case '"', '\\':
+			sb.WriteByte(body[i])
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'x', 'u', 'U':
+			digits := 2
+			if body[i] == 'u' {
+				digits = 4
+			} else if body[i] == 'U' {
+				digits = 8
+			}
+			if i+digits >= len(body) {
+				return "", fmt.Errorf("invalid escape sequence in K string literal: %s", literal)
+			}
+			code, err := strconv.ParseUint(body[i+1:i+1+digits], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid escape sequence in K string literal: %s", literal)
+			}
+			if body[i] == 'x' {
+				sb.WriteByte(byte(code))
+			} else {
+				sb.WriteRune(rune(code))
+			}
+			i += digits
+		default:
+			return "", fmt.Errorf("invalid escape sequence in K string literal: %s", literal)
+		}
+	}
+	return sb.String(), nil
+}
+
+// kastListItems flattens a cons list, e.g. `_,__IELE-DATA`(item1, `_,__IELE-DATA`(item2, `.List{...}`(.KList))).
+func (profile *LabelProfile) kastListItems(term *kastTerm) ([]*kastTerm, error) {
+	var items []*kastTerm
+	for {
+		switch {
+		case !term.isToken && term.label == profile.Cons && len(term.args) == 2:
+			items = append(items, term.args[0])
+			term = term.args[1]
+		case !term.isToken && term.label == profile.Nil && len(term.args) == 0:
+			return items, nil
+		default:
+			return nil, oj.ErrorAt(term.pos, fmt.Errorf("unexpected term in list: %s", term.describe()))
+		}
+	}
+}
+
+// describe yields a short description of the term, for error messages.
+func (term *kastTerm) describe() string {
+	if term.isToken {
+		return fmt.Sprintf("#token(%q,%q)", term.text, term.sort)
+	}
+	return fmt.Sprintf("`%s` with %d arguments", term.label, len(term.args))
+}
+
+func (profile *LabelProfile) kastTermToJSON(term *kastTerm) (oj.OJsonObject, error) {
+	if term.isToken {
+		return profile.kastTokenToJSON(term)
+	}
+	switch {
+	case term.label == profile.Map && len(term.args) == 1:
+		items, err := profile.kastListItems(term.args[0])
+		if err != nil {
+			return nil, err
+		}
+		result := oj.NewMap()
+		result.Pos = term.pos
+		for _, item := range items {
+			if item.isToken || item.label != profile.Entry || len(item.args) != 2 {
+				return nil, oj.ErrorAt(item.pos, fmt.Errorf("map entry expected, got %s", item.describe()))
+			}
+			key, err := profile.kastTermToJSON(item.args[0])
+			if err != nil {
+				return nil, err
+			}
+			keyStr, isStr := key.(*oj.OJsonString)
+			if !isStr {
+				return nil, oj.ErrorAt(item.args[0].pos, errors.New("map key must be a string"))
+			}
+			if _, exists := result.Get(keyStr.Value); exists {
+				return nil, oj.ErrorAt(item.args[0].pos, fmt.Errorf("duplicate key: %s", keyStr.Value))
+			}
+			value, err := profile.kastTermToJSON(item.args[1])
+			if err != nil {
+				return nil, err
+			}
+			result.Put(keyStr.Value, value)
+		}
+		return result, nil
+	case term.label == profile.List && len(term.args) == 1:
+		items, err := profile.kastListItems(term.args[0])
+		if err != nil {
+			return nil, err
+		}
+		result := &oj.OJsonList{Pos: term.pos}
+		for _, item := range items {
+			value, err := profile.kastTermToJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			result.Items = append(result.Items, value)
+		}
+		return result, nil
+	case term.label == profile.Null && len(term.args) == 0:
+		return &oj.OJsonNull{Pos: term.pos}, nil
+	default:
+		return nil, oj.ErrorAt(term.pos, fmt.Errorf("unexpected term for the %s profile: %s", profile.Name, term.describe()))
+	}
+}
+
+func (profile *LabelProfile) kastTokenToJSON(term *kastTerm) (oj.OJsonObject, error) {
+	switch term.sort {
+	case profile.StringSort:
+		value, err := unquoteKString(term.text)
+		if err != nil {
+			return nil, oj.ErrorAt(term.pos, err)
+		}
+		return &oj.OJsonString{Value: value, Pos: term.pos}, nil
+	case profile.BoolSort:
+		if term.text != "true" && term.text != "false" {
+			return nil, oj.ErrorAt(term.pos, fmt.Errorf("invalid Bool token: %s", term.text))
+		}
+		return &oj.OJsonBool{Value: term.text == "true", Pos: term.pos}, nil
+	case profile.IntSort, profile.FloatSort:
+		number, err := oj.ParseOrderedJSON([]byte(term.text))
+		if _, isNumber := number.(*oj.OJsonNumber); err != nil || !isNumber {
+			return nil, oj.ErrorAt(term.pos, fmt.Errorf("invalid %s token: %s", term.sort, term.text))
+		}
+		return &oj.OJsonNumber{Value: term.text, Pos: term.pos}, nil
+	default:
+		return nil, oj.ErrorAt(term.pos, fmt.Errorf("unsupported token sort: %s", term.sort))
+	}
+}
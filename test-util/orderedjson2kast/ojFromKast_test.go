@@ -0,0 +1,62 @@
+package orderedjson2kast
+
+import (
+	"io/ioutil"
+	"testing"
+
+	oj "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKastRoundTrip(t *testing.T) {
+	contents, err := ioutil.ReadFile("../mandos/json/integrationTests/example.scen.json")
+	require.Nil(t, err)
+	jobj, err := oj.ParseOrderedJSON(contents)
+	require.Nil(t, err)
+
+	back, err := ConvertKastToOrderedJSON(jsonToKastOrdered(jobj, IELEProfile()))
+	require.Nil(t, err)
+	require.Equal(t, oj.JSONString(jobj), oj.JSONString(back))
+
+	jobj, err = oj.ParseOrderedJSON([]byte(`{"s": "quote \" backslash \\ tab \t ctrl \u0001 ünï", "l": [], "m": {}, "n": [-1, 2.5e3, true, false, null]}`))
+	require.Nil(t, err)
+	back, err = ConvertKastToOrderedJSON(jsonToKastOrdered(jobj, IELEProfile()))
+	require.Nil(t, err)
+	require.Equal(t, oj.JSONString(jobj), oj.JSONString(back))
+}
+
+func TestKastParseErrors(t *testing.T) {
+	_, err := ConvertKastToOrderedJSON("`[_]_IELE-DATA`(`_,__IELE-DATA`(#token(\"1\",\"Int\"),\n  #token(\"x\",\"Id\")))")
+	require.Equal(t, "2:3: unexpected term in list: #token(\"x\",\"Id\")", err.Error())
+
+	_, err = ConvertKastToOrderedJSON("#token(\"1\",\"Bytes\")")
+	require.Equal(t, "1:1: unsupported token sort: Bytes", err.Error())
+
+	_, err = ConvertKastToOrderedJSON("`null_IELE-DATA`(.KList")
+	require.Equal(t, "1:24: unexpected end of input, ')' expected", err.Error())
+}
+
+func TestLabelProfiles(t *testing.T) {
+	jobj, err := oj.ParseOrderedJSON([]byte(`{"a": [1, true], "b": null}`))
+	require.Nil(t, err)
+
+	require.Equal(t,
+		"`{_}_IELE-DATA`(`_,__IELE-DATA`(`_:__IELE-DATA`(#token(\"\\\"a\\\"\",\"String\"),"+
+			"`[_]_IELE-DATA`(`_,__IELE-DATA`(#token(\"1\",\"Int\"),`_,__IELE-DATA`(#token(\"true\",\"Bool\"),`.List{\"_,__IELE-DATA\"}`(.KList))))),"+
+			"`_,__IELE-DATA`(`_:__IELE-DATA`(#token(\"\\\"b\\\"\",\"String\"),`null_IELE-DATA`(.KList)),`.List{\"_,__IELE-DATA\"}`(.KList))))",
+		jsonToKastOrdered(jobj, IELEProfile()))
+
+	mandosKast := jsonToKastOrdered(jobj, MandosWasmProfile())
+	require.Equal(t,
+		"`JSONObject`(`JSONs`(`JSONEntry`(#token(\"\\\"a\\\"\",\"String\"),"+
+			"`JSONList`(`JSONs`(#token(\"1\",\"Int\"),`JSONs`(#token(\"true\",\"Bool\"),`.List{\"JSONs\"}`(.KList))))),"+
+			"`JSONs`(`JSONEntry`(#token(\"\\\"b\\\"\",\"String\"),`JSONnull`(.KList)),`.List{\"JSONs\"}`(.KList))))",
+		mandosKast)
+
+	back, err := ConvertKastToOrderedJSONWithProfile(mandosKast, MandosWasmProfile())
+	require.Nil(t, err)
+	require.True(t, oj.Equal(jobj, back))
+
+	_, err = ConvertKastToOrderedJSON(mandosKast)
+	require.Equal(t, "1:1: unexpected term for the IELE profile: `JSONObject` with 1 arguments", err.Error())
+}
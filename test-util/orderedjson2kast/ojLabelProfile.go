@@ -0,0 +1,82 @@
+package orderedjson2kast
+
+// LabelProfile names the K labels and sorts that represent JSON in a particular K semantics.
+// Labels are given without the backquotes that surround them in KAST.
+type LabelProfile struct {
+	// Name identifies the profile in error messages.
+	Name string
+
+	// Map wraps the list of entries of a JSON map.
+	Map string
+	// List wraps the list of items of a JSON list.
+	List string
+	// Entry is a key-value pair, in a map.
+	Entry string
+	// Cons adds an entry or an item in front of a list.
+	Cons string
+	// Nil is the empty list of entries or items.
+	Nil string
+	// Null is the JSON null.
+	Null string
+
+	// Bytes is an interpreted mandos value, applied to the bytes and to the original string.
+	Bytes string
+	// Integer is an interpreted mandos number, applied to the integer and to the original string.
+	Integer string
+	// Star is a check that accepts any value, applied to the original string.
+	Star string
+
+	// StringSort is the sort of string tokens, their text is a K string literal.
+	StringSort string
+	// IntSort is the sort of integer number tokens.
+	IntSort string
+	// FloatSort is the sort of the other number tokens.
+	FloatSort string
+	// BoolSort is the sort of the true and false tokens.
+	BoolSort string
+	// BytesSort is the sort of byte tokens, their text is a K Bytes literal.
+	BytesSort string
+}
+
+// IELEProfile yields the labels of the IELE semantics. This is the default.
+func IELEProfile() *LabelProfile {
+	return &LabelProfile{
+		Name:       "IELE",
+		Map:        "{_}_IELE-DATA",
+		List:       "[_]_IELE-DATA",
+		Entry:      "_:__IELE-DATA",
+		Cons:       "_,__IELE-DATA",
+		Nil:        ".List{\"_,__IELE-DATA\"}",
+		Null:       "null_IELE-DATA",
+		Bytes:      "bytes(_,_)_IELE-DATA",
+		Integer:    "int(_,_)_IELE-DATA",
+		Star:       "*(_)_IELE-DATA",
+		StringSort: "String",
+		IntSort:    "Int",
+		FloatSort:  "Float",
+		BoolSort:   "Bool",
+		BytesSort:  "Bytes",
+	}
+}
+
+// MandosWasmProfile yields the labels of the Elrond WASM semantics,
+// which reads mandos scenarios using the JSON module that comes with K.
+func MandosWasmProfile() *LabelProfile {
+	return &LabelProfile{
+		Name:       "Mandos/WASM",
+		Map:        "JSONObject",
+		List:       "JSONList",
+		Entry:      "JSONEntry",
+		Cons:       "JSONs",
+		Nil:        ".List{\"JSONs\"}",
+		Null:       "JSONnull",
+		Bytes:      "MandosBytes",
+		Integer:    "MandosInt",
+		Star:       "MandosStar",
+		StringSort: "String",
+		IntSort:    "Int",
+		FloatSort:  "Float",
+		BoolSort:   "Bool",
+		BytesSort:  "Bytes",
+	}
+}
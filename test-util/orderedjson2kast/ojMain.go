@@ -6,20 +6,30 @@ import (
 	oj "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson"
 )
 
-// ProcessCodeFunc represents a callback to assemble the code in the test
-type ProcessCodeFunc func(testPath string, value string) string
+// ProcessCodeFunc represents a callback to assemble the code in the test.
+// Errors stop the conversion.
+type ProcessCodeFunc func(testPath string, value string) (string, error)
 
 // ConvertOrderedJSONToKast parses data as an ordered JSON,
 // assembles code if necessary
-// and converts to KAST format, readable by K
+// and converts to KAST format, readable by K.
+// Errors from the callback are returned with the JSON path of the code field.
+// The labels are those of the IELE semantics.
 func ConvertOrderedJSONToKast(data []byte, testFilePath string, processCodeCallback ProcessCodeFunc) (string, error) {
+	return ConvertOrderedJSONToKastWithProfile(data, testFilePath, processCodeCallback, IELEProfile())
+}
+
+// ConvertOrderedJSONToKastWithProfile is ConvertOrderedJSONToKast, with the labels of the given profile.
+func ConvertOrderedJSONToKastWithProfile(data []byte, testFilePath string, processCodeCallback ProcessCodeFunc, profile *LabelProfile) (string, error) {
 	jsonObj, err := oj.ParseOrderedJSON(data)
 	if err != nil {
 		return "", err
 	}
 	testDirPath := filepath.Dir(testFilePath)
-	processTestCode(jsonObj, testDirPath, processCodeCallback)
-	kast := jsonToKastOrdered(jsonObj)
+	if err := processTestCode(jsonObj, testDirPath, processCodeCallback); err != nil {
+		return "", err
+	}
+	kast := jsonToKastOrdered(jsonObj, profile)
 
 	return kast, nil
 }
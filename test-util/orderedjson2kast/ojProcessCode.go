@@ -1,21 +1,26 @@
 package orderedjson2kast
 
 import (
+	"fmt"
+
 	oj "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson"
 )
 
-func processTestCode(jobj oj.OJsonObject, testPath string, processCodeCallback ProcessCodeFunc) {
-	switch j := jobj.(type) {
-	case *oj.OJsonMap:
+func processTestCode(jobj oj.OJsonObject, testPath string, processCodeCallback ProcessCodeFunc) error {
+	var err error
+	oj.Walk(jobj, func(path oj.Pointer, node oj.OJsonObject) bool {
+		if err != nil {
+			return false
+		}
+		j, isMap := node.(*oj.OJsonMap)
+		if !isMap {
+			return true
+		}
+
 		isCreateTx := false
-		for _, keyValuePair := range j.OrderedKV {
-			if keyValuePair.Key == "to" {
-				if strVal, isStr := keyValuePair.Value.(*oj.OJsonString); isStr {
-					if strVal.Value == "" {
-						isCreateTx = true
-						break
-					}
-				}
+		if to, hasTo := j.Get("to"); hasTo {
+			if strVal, isStr := to.(*oj.OJsonString); isStr && strVal.Value == "" {
+				isCreateTx = true
 			}
 		}
 
@@ -23,17 +28,19 @@ func processTestCode(jobj oj.OJsonObject, testPath string, processCodeCallback P
 			if keyValuePair.Key == "code" ||
 				(keyValuePair.Key == "contractCode" && isCreateTx) {
 				if strVal, isStr := keyValuePair.Value.(*oj.OJsonString); isStr {
-					strVal.Value = processCodeCallback(testPath, strVal.Value)
+					processed, processErr := processCodeCallback(testPath, strVal.Value)
+					if processErr != nil {
+						err = fmt.Errorf("cannot process code at %s: %w", path.Append(keyValuePair.Key), processErr)
+						if strVal.Pos.IsValid() {
+							err = oj.ErrorAt(strVal.Pos, err)
+						}
+						return false
+					}
+					strVal.Value = processed
 				}
-			} else {
-				processTestCode(keyValuePair.Value, testPath, processCodeCallback)
 			}
 		}
-	case *oj.OJsonList:
-		collection := []oj.OJsonObject(*j)
-		for _, elem := range collection {
-			processTestCode(elem, testPath, processCodeCallback)
-		}
-	default:
-	}
+		return true
+	})
+	return err
 }
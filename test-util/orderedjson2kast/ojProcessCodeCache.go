@@ -0,0 +1,69 @@
+package orderedjson2kast
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const codeFilePrefix = "file:"
+
+type codeCacheKey struct {
+	path    string
+	modTime time.Time
+}
+
+// MemoizeProcessCode wraps a code processing callback, so that each contract file gets processed only once,
+// e.g. when converting a whole test suite. Results are keyed by the absolute path of the file and its modification time,
+// so a contract that changes in between gets processed again.
+// Values that do not name an existing file, and errors, are not cached.
+// The wrapper is safe to use from several goroutines.
+func MemoizeProcessCode(processCode ProcessCodeFunc) ProcessCodeFunc {
+	var mutex sync.Mutex
+	cache := make(map[codeCacheKey]string)
+
+	return func(testPath string, value string) (string, error) {
+		key, isFile := resolveCodeFile(testPath, value)
+		if !isFile {
+			return processCode(testPath, value)
+		}
+
+		mutex.Lock()
+		result, found := cache[key]
+		mutex.Unlock()
+		if found {
+			return result, nil
+		}
+
+		result, err := processCode(testPath, value)
+		if err != nil {
+			return "", err
+		}
+		mutex.Lock()
+		cache[key] = result
+		mutex.Unlock()
+		return result, nil
+	}
+}
+
+// resolveCodeFile finds the file a code value refers to, relative to the test directory.
+func resolveCodeFile(testPath string, value string) (codeCacheKey, bool) {
+	path := strings.TrimPrefix(value, codeFilePrefix)
+	if len(path) == 0 {
+		return codeCacheKey{}, false
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(testPath, path)
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return codeCacheKey{}, false
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return codeCacheKey{}, false
+	}
+	return codeCacheKey{path: path, modTime: info.ModTime()}, true
+}
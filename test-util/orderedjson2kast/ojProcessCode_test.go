@@ -0,0 +1,55 @@
+package orderedjson2kast
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessCodeError(t *testing.T) {
+	failing := func(testPath string, value string) (string, error) {
+		return "", errors.New("assembler failed")
+	}
+	_, err := ConvertOrderedJSONToKast([]byte(`{
+	"pre": {
+		"0x01": {"code": "file:missing.iele"}
+	}
+}`), "test.json", failing)
+	require.Equal(t, "3:20: cannot process code at /pre/0x01/code: assembler failed", err.Error())
+}
+
+func TestMemoizeProcessCode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kastcode")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "a.iele"), []byte("contract"), 0644))
+
+	calls := 0
+	processCode := MemoizeProcessCode(func(testPath string, value string) (string, error) {
+		calls++
+		return "assembled", nil
+	})
+
+	for i := 0; i < 2; i++ {
+		result, err := processCode(dir, "file:a.iele")
+		require.Nil(t, err)
+		require.Equal(t, "assembled", result)
+	}
+	_, _ = processCode(filepath.Join(dir, "sub", ".."), "a.iele")
+	require.Equal(t, 1, calls)
+
+	later := time.Now().Add(time.Hour)
+	require.Nil(t, os.Chtimes(filepath.Join(dir, "a.iele"), later, later))
+	_, _ = processCode(dir, "file:a.iele")
+	require.Equal(t, 2, calls)
+
+	// not files, never cached
+	_, _ = processCode(dir, "0x1234")
+	_, _ = processCode(dir, "0x1234")
+	require.Equal(t, 4, calls)
+}
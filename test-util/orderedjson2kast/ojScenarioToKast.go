@@ -0,0 +1,355 @@
+package orderedjson2kast
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	mj "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/model"
+	oj "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson"
+)
+
+// ConvertScenarioToKast converts a parsed scenario to KAST, readable by K.
+// Unlike ConvertOrderedJSONToKast, the mandos values are already interpreted:
+// each becomes a Bytes or an Int, next to the string it was originally written as,
+// so the K semantics sees exactly the same inputs as the Go executors.
+//
+// The structure follows the JSON of the scenario, with two differences:
+// accounts and storage are lists of maps, with "address", "key" and "value" entries,
+// since their keys are values themselves,
+// and checks that accept anything are the Star label, with the original string ("" if missing).
+func ConvertScenarioToKast(scenario *mj.Scenario, profile *LabelProfile) string {
+	var sb strings.Builder
+	writeTerm(&sb, profile.scenarioTerm(scenario))
+	return sb.String()
+}
+
+// kastEntry is a map entry, while building a term.
+type kastEntry struct {
+	key   string
+	value *kastTerm
+}
+
+func (profile *LabelProfile) mapTerm(entries []kastEntry) *kastTerm {
+	items := make([]*kastTerm, len(entries))
+	for i, entry := range entries {
+		items[i] = &kastTerm{
+			label: profile.Entry,
+			args:  []*kastTerm{profile.stringTerm(entry.key), entry.value},
+		}
+	}
+	return &kastTerm{label: profile.Map, args: []*kastTerm{profile.consTerm(items)}}
+}
+
+func (profile *LabelProfile) listTerm(items []*kastTerm) *kastTerm {
+	return &kastTerm{label: profile.List, args: []*kastTerm{profile.consTerm(items)}}
+}
+
+func (profile *LabelProfile) consTerm(items []*kastTerm) *kastTerm {
+	result := &kastTerm{label: profile.Nil}
+	for i := len(items) - 1; i >= 0; i-- {
+		result = &kastTerm{label: profile.Cons, args: []*kastTerm{items[i], result}}
+	}
+	return result
+}
+
+func (profile *LabelProfile) stringTerm(value string) *kastTerm {
+	return &kastTerm{isToken: true, text: quoteKString(value), sort: profile.StringSort}
+}
+
+func (profile *LabelProfile) boolTerm(value bool) *kastTerm {
+	return &kastTerm{isToken: true, text: fmt.Sprintf("%t", value), sort: profile.BoolSort}
+}
+
+func (profile *LabelProfile) bytesTerm(value []byte, original string) *kastTerm {
+	return &kastTerm{
+		label: profile.Bytes,
+		args: []*kastTerm{
+			{isToken: true, text: quoteKBytes(value), sort: profile.BytesSort},
+			profile.stringTerm(original),
+		},
+	}
+}
+
+func (profile *LabelProfile) intTerm(value *big.Int, original string) *kastTerm {
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	return &kastTerm{
+		label: profile.Integer,
+		args: []*kastTerm{
+			{isToken: true, text: value.String(), sort: profile.IntSort},
+			profile.stringTerm(original),
+		},
+	}
+}
+
+func (profile *LabelProfile) starTerm(original string) *kastTerm {
+	return &kastTerm{label: profile.Star, args: []*kastTerm{profile.stringTerm(original)}}
+}
+
+// originalTreeString yields the original of a value given as a tree:
+// strings as they are, lists as compact JSON.
+func originalTreeString(original oj.OJsonObject) string {
+	if str, isStr := original.(*oj.OJsonString); isStr {
+		return str.Value
+	}
+	if original == nil {
+		return ""
+	}
+	data, err := json.Marshal(original)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func (profile *LabelProfile) bytesFromStringTerm(jb mj.JSONBytesFromString) *kastTerm {
+	return profile.bytesTerm(jb.Value, jb.Original)
+}
+
+func (profile *LabelProfile) bytesFromTreeTerm(jb mj.JSONBytesFromTree) *kastTerm {
+	return profile.bytesTerm(jb.Value, originalTreeString(jb.Original))
+}
+
+func (profile *LabelProfile) bigIntTerm(jbi mj.JSONBigInt) *kastTerm {
+	return profile.intTerm(jbi.Value, jbi.Original)
+}
+
+func (profile *LabelProfile) uint64Term(ju mj.JSONUint64) *kastTerm {
+	return profile.intTerm(new(big.Int).SetUint64(ju.Value), ju.Original)
+}
+
+func (profile *LabelProfile) checkBytesTerm(jcbytes mj.JSONCheckBytes) *kastTerm {
+	if jcbytes.IsStar {
+		return profile.starTerm(originalTreeString(jcbytes.Original))
+	}
+	return profile.bytesTerm(jcbytes.Value, originalTreeString(jcbytes.Original))
+}
+
+func (profile *LabelProfile) checkBigIntTerm(jcbi mj.JSONCheckBigInt) *kastTerm {
+	if jcbi.IsStar {
+		return profile.starTerm(jcbi.Original)
+	}
+	return profile.intTerm(jcbi.Value, jcbi.Original)
+}
+
+func (profile *LabelProfile) checkUint64Term(jcu mj.JSONCheckUint64) *kastTerm {
+	if jcu.IsStar {
+		return profile.starTerm(jcu.Original)
+	}
+	return profile.intTerm(new(big.Int).SetUint64(jcu.Value), jcu.Original)
+}
+
+func (profile *LabelProfile) scenarioTerm(scenario *mj.Scenario) *kastTerm {
+	var entries []kastEntry
+	if len(scenario.Name) > 0 {
+		entries = append(entries, kastEntry{"name", profile.stringTerm(scenario.Name)})
+	}
+	if len(scenario.Comment) > 0 {
+		entries = append(entries, kastEntry{"comment", profile.stringTerm(scenario.Comment)})
+	}
+	entries = append(entries, kastEntry{"checkGas", profile.boolTerm(scenario.CheckGas)})
+
+	var steps []*kastTerm
+	for _, step := range scenario.Steps {
+		steps = append(steps, profile.stepTerm(step))
+	}
+	entries = append(entries, kastEntry{"steps", profile.listTerm(steps)})
+	return profile.mapTerm(entries)
+}
+
+func (profile *LabelProfile) stepTerm(generalStep mj.Step) *kastTerm {
+	entries := []kastEntry{{"step", profile.stringTerm(generalStep.StepTypeName())}}
+	comment := func(comment string) {
+		if len(comment) > 0 {
+			entries = append(entries, kastEntry{"comment", profile.stringTerm(comment)})
+		}
+	}
+	switch step := generalStep.(type) {
+	case *mj.ExternalStepsStep:
+		entries = append(entries, kastEntry{"path", profile.stringTerm(step.Path)})
+	case *mj.SetStateStep:
+		comment(step.Comment)
+		entries = append(entries, kastEntry{"accounts", profile.accountsTerm(step.Accounts)})
+		var newAddresses []*kastTerm
+		for _, nam := range step.NewAddressMocks {
+			newAddresses = append(newAddresses, profile.mapTerm([]kastEntry{
+				{"creatorAddress", profile.bytesFromStringTerm(nam.CreatorAddress)},
+				{"creatorNonce", profile.uint64Term(nam.CreatorNonce)},
+				{"newAddress", profile.bytesFromStringTerm(nam.NewAddress)},
+			}))
+		}
+		entries = append(entries, kastEntry{"newAddresses", profile.listTerm(newAddresses)})
+		if step.PreviousBlockInfo != nil {
+			entries = append(entries, kastEntry{"previousBlockInfo", profile.blockInfoTerm(step.PreviousBlockInfo)})
+		}
+		if step.CurrentBlockInfo != nil {
+			entries = append(entries, kastEntry{"currentBlockInfo", profile.blockInfoTerm(step.CurrentBlockInfo)})
+		}
+		var blockHashes []*kastTerm
+		for _, blockHash := range step.BlockHashes {
+			blockHashes = append(blockHashes, profile.bytesFromStringTerm(blockHash))
+		}
+		entries = append(entries, kastEntry{"blockHashes", profile.listTerm(blockHashes)})
+	case *mj.CheckStateStep:
+		comment(step.Comment)
+		entries = append(entries, kastEntry{"accounts", profile.checkAccountsTerm(step.CheckAccounts)})
+	case *mj.DumpStateStep:
+		comment(step.Comment)
+	case *mj.TxStep:
+		if len(step.TxIdent) > 0 {
+			entries = append(entries, kastEntry{"txId", profile.stringTerm(step.TxIdent)})
+		}
+		comment(step.Comment)
+		entries = append(entries, kastEntry{"tx", profile.transactionTerm(step.Tx)})
+		if step.Tx.Type.IsSmartContractTx() && step.ExpectedResult != nil {
+			entries = append(entries, kastEntry{"expect", profile.resultTerm(step.ExpectedResult)})
+		}
+	}
+	return profile.mapTerm(entries)
+}
+
+func (profile *LabelProfile) accountsTerm(accounts []*mj.Account) *kastTerm {
+	var items []*kastTerm
+	for _, account := range accounts {
+		entries := []kastEntry{{"address", profile.bytesFromStringTerm(account.Address)}}
+		if len(account.Comment) > 0 {
+			entries = append(entries, kastEntry{"comment", profile.stringTerm(account.Comment)})
+		}
+		entries = append(entries,
+			kastEntry{"nonce", profile.uint64Term(account.Nonce)},
+			kastEntry{"balance", profile.bigIntTerm(account.Balance)},
+			kastEntry{"storage", profile.storageTerm(account.Storage)},
+			kastEntry{"code", profile.bytesFromStringTerm(account.Code)},
+		)
+		if len(account.AsyncCallData) > 0 {
+			entries = append(entries, kastEntry{"asyncCallData", profile.stringTerm(account.AsyncCallData)})
+		}
+		items = append(items, profile.mapTerm(entries))
+	}
+	return profile.listTerm(items)
+}
+
+func (profile *LabelProfile) checkAccountsTerm(checkAccounts *mj.CheckAccounts) *kastTerm {
+	var items []*kastTerm
+	for _, checkAccount := range checkAccounts.Accounts {
+		entries := []kastEntry{{"address", profile.bytesFromStringTerm(checkAccount.Address)}}
+		if len(checkAccount.Comment) > 0 {
+			entries = append(entries, kastEntry{"comment", profile.stringTerm(checkAccount.Comment)})
+		}
+		storage := profile.starTerm("*")
+		if !checkAccount.IgnoreStorage {
+			storage = profile.storageTerm(checkAccount.CheckStorage)
+		}
+		entries = append(entries,
+			kastEntry{"nonce", profile.checkUint64Term(checkAccount.Nonce)},
+			kastEntry{"balance", profile.checkBigIntTerm(checkAccount.Balance)},
+			kastEntry{"storage", storage},
+			kastEntry{"code", profile.checkBytesTerm(checkAccount.Code)},
+			kastEntry{"asyncCallData", profile.checkBytesTerm(checkAccount.AsyncCallData)},
+		)
+		items = append(items, profile.mapTerm(entries))
+	}
+	return profile.mapTerm([]kastEntry{
+		{"accounts", profile.listTerm(items)},
+		{"otherAccountsAllowed", profile.boolTerm(checkAccounts.OtherAccountsAllowed)},
+	})
+}
+
+func (profile *LabelProfile) storageTerm(storage []*mj.StorageKeyValuePair) *kastTerm {
+	var items []*kastTerm
+	for _, st := range storage {
+		items = append(items, profile.mapTerm([]kastEntry{
+			{"key", profile.bytesFromStringTerm(st.Key)},
+			{"value", profile.bytesFromTreeTerm(st.Value)},
+		}))
+	}
+	return profile.listTerm(items)
+}
+
+func (profile *LabelProfile) blockInfoTerm(blockInfo *mj.BlockInfo) *kastTerm {
+	var entries []kastEntry
+	if len(blockInfo.BlockTimestamp.Original) > 0 {
+		entries = append(entries, kastEntry{"blockTimestamp", profile.uint64Term(blockInfo.BlockTimestamp)})
+	}
+	if len(blockInfo.BlockNonce.Original) > 0 {
+		entries = append(entries, kastEntry{"blockNonce", profile.uint64Term(blockInfo.BlockNonce)})
+	}
+	if len(blockInfo.BlockRound.Original) > 0 {
+		entries = append(entries, kastEntry{"blockRound", profile.uint64Term(blockInfo.BlockRound)})
+	}
+	if len(blockInfo.BlockEpoch.Original) > 0 {
+		entries = append(entries, kastEntry{"blockEpoch", profile.uint64Term(blockInfo.BlockEpoch)})
+	}
+	return profile.mapTerm(entries)
+}
+
+func (profile *LabelProfile) transactionTerm(tx *mj.Transaction) *kastTerm {
+	var entries []kastEntry
+	if tx.Type.HasSender() {
+		entries = append(entries, kastEntry{"from", profile.bytesFromStringTerm(tx.From)})
+	}
+	if tx.Type.HasReceiver() {
+		entries = append(entries, kastEntry{"to", profile.bytesFromStringTerm(tx.To)})
+	}
+	entries = append(entries, kastEntry{"value", profile.bigIntTerm(tx.Value)})
+	if tx.Type == mj.ScCall {
+		entries = append(entries, kastEntry{"function", profile.stringTerm(tx.Function)})
+	}
+	if tx.Type == mj.ScDeploy {
+		entries = append(entries, kastEntry{"contractCode", profile.bytesFromStringTerm(tx.Code)})
+	}
+	if tx.Type.IsSmartContractTx() {
+		var arguments []*kastTerm
+		for _, arg := range tx.Arguments {
+			arguments = append(arguments, profile.bytesFromTreeTerm(arg))
+		}
+		entries = append(entries,
+			kastEntry{"arguments", profile.listTerm(arguments)},
+			kastEntry{"gasLimit", profile.uint64Term(tx.GasLimit)},
+			kastEntry{"gasPrice", profile.uint64Term(tx.GasPrice)},
+		)
+	}
+	return profile.mapTerm(entries)
+}
+
+func (profile *LabelProfile) resultTerm(result *mj.TransactionResult) *kastTerm {
+	var out []*kastTerm
+	for _, item := range result.Out {
+		out = append(out, profile.checkBytesTerm(item))
+	}
+
+	var logs *kastTerm
+	switch {
+	case result.IgnoreLogs:
+		logs = profile.starTerm("*")
+	case len(result.LogHash) > 0:
+		logs = profile.stringTerm(result.LogHash)
+	default:
+		var items []*kastTerm
+		for _, logEntry := range result.Logs {
+			var topics []*kastTerm
+			for _, topic := range logEntry.Topics {
+				topics = append(topics, profile.bytesFromStringTerm(topic))
+			}
+			items = append(items, profile.mapTerm([]kastEntry{
+				{"address", profile.bytesFromStringTerm(logEntry.Address)},
+				{"identifier", profile.bytesFromStringTerm(logEntry.Identifier)},
+				{"topics", profile.listTerm(topics)},
+				{"data", profile.bytesFromStringTerm(logEntry.Data)},
+			}))
+		}
+		logs = profile.listTerm(items)
+	}
+
+	return profile.mapTerm([]kastEntry{
+		{"out", profile.listTerm(out)},
+		{"status", profile.checkBigIntTerm(result.Status)},
+		{"message", profile.checkBytesTerm(result.Message)},
+		{"logs", logs},
+		{"gas", profile.checkUint64Term(result.Gas)},
+		{"refund", profile.checkBigIntTerm(result.Refund)},
+	})
+}
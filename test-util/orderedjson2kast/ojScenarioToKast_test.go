@@ -0,0 +1,54 @@
+package orderedjson2kast
+
+import (
+	"strings"
+	"testing"
+
+	fr "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/fileresolver"
+	mjparse "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScenarioToKast(t *testing.T) {
+	p := mjparse.NewParser(fr.NewDefaultFileResolver())
+	scenario, err := p.ParseScenarioFile([]byte(`{
+		"steps": [
+			{
+				"step": "scCall",
+				"tx": {
+					"from": "address:a",
+					"to": "address:b",
+					"value": "1,000",
+					"function": "f",
+					"arguments": ["''a|u8:1", "u32:5", ["''b", "0x00"]],
+					"gasLimit": "0x10",
+					"gasPrice": "0"
+				},
+				"expect": {
+					"out": ["*"],
+					"status": "0"
+				}
+			}
+		]
+	}`))
+	require.Nil(t, err)
+
+	kast := ConvertScenarioToKast(scenario, MandosWasmProfile())
+	require.True(t, strings.HasPrefix(kast, "`JSONObject`(`JSONs`(`JSONEntry`(#token(\"\\\"checkGas\\\"\",\"String\"),#token(\"true\",\"Bool\")),"))
+	require.Contains(t, kast, "`MandosBytes`(#token(\"b\\\"a\\\\x01\\\"\",\"Bytes\"),#token(\"\\\"''a|u8:1\\\"\",\"String\"))")
+	require.Contains(t, kast, "`MandosInt`(#token(\"1000\",\"Int\"),#token(\"\\\"1,000\\\"\",\"String\"))")
+	require.Contains(t, kast, "`MandosBytes`(#token(\"b\\\"\\\\x00\\\\x00\\\\x00\\\\x05\\\"\",\"Bytes\"),#token(\"\\\"u32:5\\\"\",\"String\"))")
+	require.Contains(t, kast, "`MandosBytes`(#token(\"b\\\"b\\\\x00\\\"\",\"Bytes\"),#token(\"\\\"[\\\\\\\"''b\\\\\\\",\\\\\\\"0x00\\\\\\\"]\\\"\",\"String\"))")
+	require.Contains(t, kast, "`JSONEntry`(#token(\"\\\"out\\\"\",\"String\"),`JSONList`(`JSONs`(`MandosStar`(#token(\"\\\"*\\\"\",\"String\")),`.List{\"JSONs\"}`(.KList))))")
+	require.Contains(t, kast, "`JSONEntry`(#token(\"\\\"gas\\\"\",\"String\"),`MandosStar`(#token(\"\\\"\\\"\",\"String\")))")
+}
+
+func TestScenarioToKore(t *testing.T) {
+	p := mjparse.NewParser(fr.NewDefaultFileResolver())
+	scenario, err := p.ParseScenarioFile([]byte(`{"steps": [{"step": "setState", "blockHashes": ["u32:5|''a"]}]}`))
+	require.Nil(t, err)
+
+	kore := ConvertScenarioToKore(scenario, IELEProfile())
+	require.Contains(t, kore, "Lbl'UndsColnUndsUnds'IELE-DATA{}(\\dv{SortString{}}(\"blockHashes\"),")
+	require.Contains(t, kore, "Lblbytes'LParUndsCommUndsRParUnds'IELE-DATA{}(\\dv{SortBytes{}}(\"\\x00\\x00\\x00\\x05a\"),\\dv{SortString{}}(\"u32:5|''a\"))")
+}
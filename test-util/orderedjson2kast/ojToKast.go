@@ -7,51 +7,149 @@ import (
 	oj "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson"
 )
 
-func jsonToKastOrdered(j oj.OJsonObject) string {
+func jsonToKastOrdered(j oj.OJsonObject, profile *LabelProfile) string {
 	var sb strings.Builder
-	writeKast(j, &sb)
+	profile.writeKast(j, &sb)
 	return sb.String()
 }
 
-func writeStringKast(sb *strings.Builder, value string) {
-	sb.WriteString(fmt.Sprintf("#token(\"\\\"%s\\\"\",\"String\")", value))
+// quoteKString produces a K string literal.
+func quoteKString(value string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch c {
+		case '"', '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case '\n':
+			sb.WriteString("\\n")
+		case '\r':
+			sb.WriteString("\\r")
+		case '\t':
+			sb.WriteString("\\t")
+		case '\f':
+			sb.WriteString("\\f")
+		default:
+			if c < 0x20 || c == 0x7f {
+				sb.WriteString(fmt.Sprintf("\\x%02x", c))
+			} else {
+				sb.WriteByte(c)
+			}
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
 }
 
-func writeKast(jobj oj.OJsonObject, sb *strings.Builder) {
-	switch j := jobj.(type) {
-	case *oj.OJsonMap:
-		sb.WriteString("`{_}_IELE-DATA`(")
-		for _, keyValuePair := range j.OrderedKV {
-			sb.WriteString("`_,__IELE-DATA`(`_:__IELE-DATA`(")
-			writeStringKast(sb, keyValuePair.Key)
-			sb.WriteString(",")
-			writeKast(keyValuePair.Value, sb)
-			sb.WriteString("),")
+// quoteKBytes produces a K Bytes literal, e.g. b"a\x00".
+func quoteKBytes(value []byte) string {
+	var sb strings.Builder
+	sb.WriteString("b\"")
+	for _, c := range value {
+		switch {
+		case c == '"' || c == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c < 0x20 || c >= 0x7f:
+			sb.WriteString(fmt.Sprintf("\\x%02x", c))
+		default:
+			sb.WriteByte(c)
 		}
-		sb.WriteString("`.List{\"_,__IELE-DATA\"}`(.KList)")
-		for i := 0; i < j.Size(); i++ {
-			sb.WriteString(")")
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// escapeKString escapes quotes and backslashes, for embedding text in a KAST string.
+func escapeKString(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "\"", "\\\"").Replace(value)
+}
+
+// writeLabel writes a label, in backquotes.
+func writeLabel(sb *strings.Builder, label string) {
+	sb.WriteString("`")
+	sb.WriteString(label)
+	sb.WriteString("`")
+}
+
+func writeToken(sb *strings.Builder, text string, sort string) {
+	sb.WriteString(fmt.Sprintf("#token(\"%s\",\"%s\")", escapeKString(text), sort))
+}
+
+// writeTerm writes a term built in memory.
+func writeTerm(sb *strings.Builder, term *kastTerm) {
+	if term.isToken {
+		writeToken(sb, term.text, term.sort)
+		return
+	}
+	writeLabel(sb, term.label)
+	sb.WriteString("(")
+	if len(term.args) == 0 {
+		sb.WriteString(".KList")
+	}
+	for i, arg := range term.args {
+		if i > 0 {
+			sb.WriteString(",")
 		}
+		writeTerm(sb, arg)
+	}
+	sb.WriteString(")")
+}
+
+// writeList writes a cons list, with the given writer for each element.
+func (profile *LabelProfile) writeList(sb *strings.Builder, length int, writeElem func(i int)) {
+	for i := 0; i < length; i++ {
+		writeLabel(sb, profile.Cons)
+		sb.WriteString("(")
+		writeElem(i)
+		sb.WriteString(",")
+	}
+	writeLabel(sb, profile.Nil)
+	sb.WriteString("(.KList)")
+	for i := 0; i < length; i++ {
 		sb.WriteString(")")
-	case *oj.OJsonList:
-		collection := []oj.OJsonObject(*j)
+	}
+}
 
-		sb.WriteString("`[_]_IELE-DATA`(")
-		for _, elem := range collection {
-			sb.WriteString("`_,__IELE-DATA`(")
-			writeKast(elem, sb)
+func (profile *LabelProfile) writeKast(jobj oj.OJsonObject, sb *strings.Builder) {
+	switch j := jobj.(type) {
+	case *oj.OJsonMap:
+		writeLabel(sb, profile.Map)
+		sb.WriteString("(")
+		profile.writeList(sb, j.Size(), func(i int) {
+			keyValuePair := j.OrderedKV[i]
+			writeLabel(sb, profile.Entry)
+			sb.WriteString("(")
+			writeToken(sb, quoteKString(keyValuePair.Key), profile.StringSort)
 			sb.WriteString(",")
-		}
-		sb.WriteString("`.List{\"_,__IELE-DATA\"}`(.KList)")
-		for i := 0; i < len(collection); i++ {
+			profile.writeKast(keyValuePair.Value, sb)
 			sb.WriteString(")")
-		}
+		})
+		sb.WriteString(")")
+	case *oj.OJsonList:
+		collection := j.AsList()
+		writeLabel(sb, profile.List)
+		sb.WriteString("(")
+		profile.writeList(sb, len(collection), func(i int) {
+			profile.writeKast(collection[i], sb)
+		})
 		sb.WriteString(")")
 	case *oj.OJsonString:
-		writeStringKast(sb, j.Value)
+		// the token text is a K string literal, which in turn gets quoted in KAST
+		writeToken(sb, quoteKString(j.Value), profile.StringSort)
 	case *oj.OJsonBool:
-		value := bool(*j)
-		sb.WriteString(fmt.Sprintf("#token(\"%t\",\"Bool\")", value))
+		writeToken(sb, fmt.Sprintf("%t", j.Value), profile.BoolSort)
+	case *oj.OJsonNumber:
+		if j.IsInteger() {
+			writeToken(sb, j.Value, profile.IntSort)
+		} else {
+			writeToken(sb, j.Value, profile.FloatSort)
+		}
+	case *oj.OJsonNull:
+		writeLabel(sb, profile.Null)
+		sb.WriteString("(.KList)")
 	default:
 		panic("unknown OJsonObject type")
 	}
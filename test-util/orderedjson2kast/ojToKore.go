@@ -0,0 +1,216 @@
+package orderedjson2kast
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	mj "github.com/ElrondNetwork/elrond-vm-util/test-util/mandos/json/model"
+	oj "github.com/ElrondNetwork/elrond-vm-util/test-util/orderedjson"
+)
+
+// ConvertOrderedJSONToKore parses data as an ordered JSON,
+// assembles code if necessary
+// and converts to KORE format, readable by the newer K backends without going through KAST.
+// The labels are those of the IELE semantics.
+func ConvertOrderedJSONToKore(data []byte, testFilePath string, processCodeCallback ProcessCodeFunc) (string, error) {
+	return ConvertOrderedJSONToKoreWithProfile(data, testFilePath, processCodeCallback, IELEProfile())
+}
+
+// ConvertOrderedJSONToKoreWithProfile is ConvertOrderedJSONToKore, with the labels of the given profile.
+func ConvertOrderedJSONToKoreWithProfile(data []byte, testFilePath string, processCodeCallback ProcessCodeFunc, profile *LabelProfile) (string, error) {
+	jsonObj, err := oj.ParseOrderedJSON(data)
+	if err != nil {
+		return "", err
+	}
+	testDirPath := filepath.Dir(testFilePath)
+	if err := processTestCode(jsonObj, testDirPath, processCodeCallback); err != nil {
+		return "", err
+	}
+	return jsonToKoreOrdered(jsonObj, profile), nil
+}
+
+// ConvertScenarioToKore is ConvertScenarioToKast, in KORE format.
+func ConvertScenarioToKore(scenario *mj.Scenario, profile *LabelProfile) string {
+	var sb strings.Builder
+	profile.writeKore(&sb, profile.scenarioTerm(scenario))
+	return sb.String()
+}
+
+func jsonToKoreOrdered(j oj.OJsonObject, profile *LabelProfile) string {
+	var sb strings.Builder
+	profile.writeKore(&sb, profile.jsonTerm(j))
+	return sb.String()
+}
+
+// jsonTerm builds the same term that writeKast writes.
+func (profile *LabelProfile) jsonTerm(jobj oj.OJsonObject) *kastTerm {
+	switch j := jobj.(type) {
+	case *oj.OJsonMap:
+		entries := make([]kastEntry, len(j.OrderedKV))
+		for i, keyValuePair := range j.OrderedKV {
+			entries[i] = kastEntry{keyValuePair.Key, profile.jsonTerm(keyValuePair.Value)}
+		}
+		return profile.mapTerm(entries)
+	case *oj.OJsonList:
+		collection := j.AsList()
+		items := make([]*kastTerm, len(collection))
+		for i, item := range collection {
+			items[i] = profile.jsonTerm(item)
+		}
+		return profile.listTerm(items)
+	case *oj.OJsonString:
+		return profile.stringTerm(j.Value)
+	case *oj.OJsonBool:
+		return profile.boolTerm(j.Value)
+	case *oj.OJsonNumber:
+		if j.IsInteger() {
+			return &kastTerm{isToken: true, text: j.Value, sort: profile.IntSort}
+		}
+		return &kastTerm{isToken: true, text: j.Value, sort: profile.FloatSort}
+	case *oj.OJsonNull:
+		return &kastTerm{label: profile.Null}
+	default:
+		panic("unknown OJsonObject type")
+	}
+}
+
+// writeKore writes a term in KORE, e.g. Lbl'UndsCommUndsUnds'IELE-DATA{}(\dv{SortInt{}}("1"),...).
+// Tokens are domain values of their own sort.
+func (profile *LabelProfile) writeKore(sb *strings.Builder, term *kastTerm) {
+	if term.isToken {
+		sb.WriteString("\\dv{")
+		sb.WriteString(koreSort(term.sort))
+		sb.WriteString("}(")
+		if term.sort == profile.BytesSort {
+			sb.WriteString(quoteKoreBytes([]byte(profile.koreTokenValue(term))))
+		} else {
+			sb.WriteString(quoteKoreString(profile.koreTokenValue(term)))
+		}
+		sb.WriteString(")")
+		return
+	}
+	sb.WriteString(koreSymbol(term.label))
+	sb.WriteString("(")
+	for i, arg := range term.args {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		profile.writeKore(sb, arg)
+	}
+	sb.WriteString(")")
+}
+
+// koreTokenValue yields the value of a token: strings and bytes are K literals in KAST, but plain in KORE.
+func (profile *LabelProfile) koreTokenValue(term *kastTerm) string {
+	literal := term.text
+	switch term.sort {
+	case profile.StringSort:
+	case profile.BytesSort:
+		literal = strings.TrimPrefix(literal, "b")
+	default:
+		return term.text
+	}
+	value, err := unquoteKString(literal)
+	if err != nil {
+		return term.text
+	}
+	return value
+}
+
+// koreEncoding names the characters that are not allowed in KORE identifiers, as the K frontend does.
+var koreEncoding = map[rune]string{
+	' ': "Spce", '!': "Bang", '"': "Quot", '#': "Hash", '$': "Dolr", '%': "Perc",
+	'&': "And-", '\'': "Apos", '(': "LPar", ')': "RPar", '*': "Star", '+': "Plus",
+	',': "Comm", '.': "Stop", '/': "Slsh", ':': "Coln", ';': "SCln", '<': "-LT-",
+	'=': "Eqls", '>': "-GT-", '?': "Ques", '@': "-AT-", '[': "LSqB", '\\': "Bash",
+	']': "RSqB", '^': "Xor-", '_': "Unds", '`': "BQuo", '{': "LBra", '|': "Pipe",
+	'}': "RBra", '~': "Tild",
+}
+
+// mangleKoreName encodes a K label or sort as a KORE identifier.
+// Runs of special characters are named and put between apostrophes, e.g. "_,__IELE-DATA" becomes "'UndsCommUndsUnds'IELE-DATA".
+func mangleKoreName(name string) string {
+	var sb strings.Builder
+	inSpecial := false
+	for _, c := range name {
+		isPlain := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-'
+		if isPlain == inSpecial {
+			sb.WriteByte('\'')
+			inSpecial = !inSpecial
+		}
+		switch {
+		case isPlain:
+			sb.WriteRune(c)
+		case koreEncoding[c] != "":
+			sb.WriteString(koreEncoding[c])
+		default:
+			sb.WriteString(fmt.Sprintf("u%04x", c))
+		}
+	}
+	if inSpecial {
+		sb.WriteByte('\'')
+	}
+	return sb.String()
+}
+
+func koreSymbol(label string) string {
+	return "Lbl" + mangleKoreName(label) + "{}"
+}
+
+func koreSort(sort string) string {
+	return "Sort" + mangleKoreName(sort) + "{}"
+}
+
+// quoteKoreString produces a KORE string literal. Bytes that are not valid UTF-8 are read as Latin-1.
+func quoteKoreString(value string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i := 0; i < len(value); {
+		c, size := utf8.DecodeRuneInString(value[i:])
+		if c == utf8.RuneError && size <= 1 {
+			c = rune(value[i])
+			size = 1
+		}
+		i += size
+		writeKoreChar(&sb, c)
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// quoteKoreBytes produces the KORE string literal of a Bytes value, one character per byte.
+func quoteKoreBytes(value []byte) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, c := range value {
+		writeKoreChar(&sb, rune(c))
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+func writeKoreChar(sb *strings.Builder, c rune) {
+	switch {
+	case c == '"' || c == '\\':
+		sb.WriteByte('\\')
+		sb.WriteRune(c)
+	case c == '\n':
+		sb.WriteString("\\n")
+	case c == '\r':
+		sb.WriteString("\\r")
+	case c == '\t':
+		sb.WriteString("\\t")
+	case c == '\f':
+		sb.WriteString("\\f")
+	case c >= 0x20 && c < 0x7f:
+		sb.WriteRune(c)
+	case c < 0x100:
+		sb.WriteString(fmt.Sprintf("\\x%02x", c))
+	case c < 0x10000:
+		sb.WriteString(fmt.Sprintf("\\u%04x", c))
+	default:
+		sb.WriteString(fmt.Sprintf("\\U%08x", c))
+	}
+}
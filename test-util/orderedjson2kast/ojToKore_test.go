@@ -0,0 +1,70 @@
+package orderedjson2kast
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// update rewrites the golden files, with: go test ./test-util/orderedjson2kast -update
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func keepCode(testPath string, value string) (string, error) {
+	return value, nil
+}
+
+func TestKastAndKoreGolden(t *testing.T) {
+	profiles := map[string]*LabelProfile{
+		"iele": IELEProfile(),
+		"wasm": MandosWasmProfile(),
+	}
+	samples, err := filepath.Glob("testdata/*.scen.json")
+	require.Nil(t, err)
+	require.NotEmpty(t, samples)
+
+	for _, samplePath := range samples {
+		contents, err := ioutil.ReadFile(samplePath)
+		require.Nil(t, err)
+		for profileName, profile := range profiles {
+			goldenPath := strings.TrimSuffix(samplePath, ".scen.json") + "." + profileName
+
+			kast, err := ConvertOrderedJSONToKastWithProfile(contents, samplePath, keepCode, profile)
+			require.Nil(t, err)
+			kore, err := ConvertOrderedJSONToKoreWithProfile(contents, samplePath, keepCode, profile)
+			require.Nil(t, err)
+
+			if *update {
+				require.Nil(t, ioutil.WriteFile(goldenPath+".kast", []byte(kast), 0644))
+				require.Nil(t, ioutil.WriteFile(goldenPath+".kore", []byte(kore), 0644))
+			}
+
+			expectedKast, err := ioutil.ReadFile(goldenPath + ".kast")
+			require.Nil(t, err)
+			require.Equal(t, string(expectedKast), kast, goldenPath)
+			expectedKore, err := ioutil.ReadFile(goldenPath + ".kore")
+			require.Nil(t, err)
+			require.Equal(t, string(expectedKore), kore, goldenPath)
+
+			// the KORE holds the same term as the KAST
+			p := &kastParser{input: kast}
+			term, err := p.parseTerm()
+			require.Nil(t, err)
+			var sb strings.Builder
+			profile.writeKore(&sb, term)
+			require.Equal(t, kore, sb.String(), goldenPath)
+		}
+	}
+}
+
+func TestKoreNames(t *testing.T) {
+	require.Equal(t, "Lbl'LBraUndsRBraUnds'IELE-DATA{}", koreSymbol("{_}_IELE-DATA"))
+	require.Equal(t, "Lbl'Stop'List'LBraQuotUndsCommUndsUnds'IELE-DATA'QuotRBra'{}", koreSymbol(".List{\"_,__IELE-DATA\"}"))
+	require.Equal(t, "LblJSONObject{}", koreSymbol("JSONObject"))
+	require.Equal(t, "SortString{}", koreSort("String"))
+	require.Equal(t, "\"a\\\"\\\\\\n\\xfc\\u20ac\"", quoteKoreString("a\"\\\nü€"))
+	require.Equal(t, "\"\\x00\\xc3\\xbc\"", quoteKoreBytes([]byte("\x00ü")))
+}